@@ -6,14 +6,22 @@ import (
 	"fmt"
 	"os"
 	"os/signal"
-	_ "path/filepath"
+	"path/filepath"
+	"regexp"
+	"strconv"
 	"strings"
 	"syscall"
 	"time"
 
+	"github.com/aadithyaa9/loganalyzer/internal/alert"
 	"github.com/aadithyaa9/loganalyzer/internal/analyzer"
+	"github.com/aadithyaa9/loganalyzer/internal/checkpoint"
+	"github.com/aadithyaa9/loganalyzer/internal/input"
+	"github.com/aadithyaa9/loganalyzer/internal/matcher"
 	"github.com/aadithyaa9/loganalyzer/internal/models"
+	"github.com/aadithyaa9/loganalyzer/internal/parser"
 	"github.com/aadithyaa9/loganalyzer/internal/reporter"
+	"github.com/aadithyaa9/loganalyzer/internal/timeexpr"
 	"github.com/aadithyaa9/loganalyzer/internal/watcher"
 	"github.com/fatih/color"
 )
@@ -43,6 +51,8 @@ func main() {
 		handleWatch()
 	case "stats":
 		handleStats()
+	case "show":
+		handleShow()
 	case "help":
 		printUsage()
 	case "version":
@@ -59,12 +69,42 @@ func handleAnalyze() {
 	fs := flag.NewFlagSet("analyze", flag.ExitOnError)
 	file := fs.String("file", "", "Single log file to analyze")
 	dir := fs.String("dir", "", "Directory containing log files")
+	rotated := fs.Bool("rotated", false, "With --file, also read its rotated siblings (app.log.1, app.log.2.gz, ...)")
 	level := fs.String("level", "", "Minimum log level (DEBUG, INFO, WARN, ERROR, FATAL)")
 	pattern := fs.String("pattern", "", "Pattern to search for")
+	regex := fs.Bool("regex", false, "Treat --pattern as a regular expression")
+	glob := fs.Bool("glob", false, "Treat --pattern as a glob (*, ?, [abc]) matching the whole message, e.g. \"*timeout*\"")
+	ignoreCase := fs.Bool("ignore-case", false, "Case-insensitive pattern matching")
 	workers := fs.Int("workers", 4, "Number of concurrent workers")
-	format := fs.String("format", "table", "Output format (table, json)")
+	format := fs.String("format", "table", "Output format (table, json, csv)")
 	output := fs.String("output", "", "Output file (default: stdout)")
+	columns := fs.String("columns", "", "CSV columns (timestamp, level, source, message, raw)")
+	csvSummary := fs.String("csv-summary", "", "Write level/source counts as CSV to this file")
+	summaryOnly := fs.Bool("summary-only", false, "Report statistics only, without entries (constant memory)")
 	topErrors := fs.Int("top-errors", 0, "Show top N error patterns")
+	showLocation := fs.Bool("show-location", false, "Show the path:line of each entry (JSON: path, line and byte offset)")
+	after := fs.Int("A", 0, "Show N entries after each match as context")
+	before := fs.Int("B", 0, "Show N entries before each match as context")
+	around := fs.Int("C", 0, "Show N entries before and after each match as context (-A/-B override)")
+	var fieldFilters stringList
+	fs.Var(&fieldFilters, "field", "Only entries whose field equals a value, as key=value (repeatable)")
+	query := fs.String("query", "", "Filter expression, e.g. 'level>=WARN and (source=\"api.log\" or msg~\"timeout\") and fields.status>=500'")
+	since := fs.String("since", "", "Only entries at or after this time (RFC3339, \"1h\", \"yesterday\", \"last monday 09:00\")")
+	until := fs.String("until", "", "Only entries at or before this time (same forms as --since)")
+	tz := fs.String("tz", "UTC", "Timezone for --since/--until without an offset (UTC, Local, +05:30, Europe/Berlin); naive log timestamps are read as UTC")
+	multiline := fs.Bool("multiline", true, "Fold stack traces and continuation lines into the preceding entry")
+	multilineStart := fs.String("multiline-start", "", "Regex matching the first line of each entry (overrides multiline heuristics)")
+	jsonProfile := fs.String("json-profile", "default", "JSON key mapping: default, pino, bunyan, zap, ecs, gcp, or a profile file")
+	logfmtKeys := fs.String("logfmt-keys", "", "Extra logfmt keys for the entry attributes, e.g. \"time=at,level=severity,msg=text\"")
+	parserName := fs.String("parser", "auto", "Parser: auto (sticky per-file detection) or "+strings.Join(parser.Names(), ", "))
+	grokExpr := fs.String("grok", "", "Grok expression, forced with --parser grok or detected alongside the others, e.g. '%{TIMESTAMP_ISO8601:ts} %{LOGLEVEL:level} %{GREEDYDATA:msg}'")
+	var grokPatterns stringList
+	fs.Var(&grokPatterns, "grok-patterns", "File of extra grok patterns, one \"NAME regex\" per line (repeatable)")
+	deadLetter := fs.String("dead-letter", "", "Write lines no parser accepted, or only plain text did, to this file, one JSON object per line")
+	strict := fs.Bool("strict", false, "Fail the run when the share of unparseable, mismatched or degraded lines exceeds --strict-threshold")
+	strictThreshold := fs.Float64("strict-threshold", 0.01, "Highest tolerated share of bad lines for --strict, from 0 to 1")
+	incremental := fs.Bool("incremental", false, "Only analyze lines added since the previous --incremental run, tracked in the state file")
+	statePath := fs.String("state", "", "State file for --incremental (default: analyze.json in the user cache directory)")
 
 	fs.Parse(os.Args[2:])
 
@@ -88,12 +128,131 @@ func handleAnalyze() {
 		}
 	}
 
+	// Compile pattern
+	m, err := buildMatcher(*pattern, *regex, *glob, *ignoreCase)
+	if err != nil {
+		fmt.Printf("❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Validate CSV columns before doing any work
+	csvColumns, err := reporter.ParseCSVColumns(*columns)
+	if err != nil {
+		fmt.Printf("❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+	if *showLocation && *columns == "" {
+		csvColumns = append(csvColumns[:len(csvColumns):len(csvColumns)], "location")
+	}
+
+	// Resolve context lines
+	beforeN, afterN, err := contextLines(fs, *before, *after, *around)
+	if err != nil {
+		fmt.Printf("❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+	if (beforeN > 0 || afterN > 0) && *columns == "" {
+		csvColumns = append(csvColumns[:len(csvColumns):len(csvColumns)], "kind")
+	}
+
+	// Resolve time range
+	startT, endT, err := parseTimeRange(*since, *until, *tz)
+	if err != nil {
+		fmt.Printf("❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Compile multiline start pattern
+	startRe, err := compileOptionalRegex(*multilineStart)
+	if err != nil {
+		fmt.Printf("❌ Error: invalid --multiline-start: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Resolve JSON key mapping profile
+	profile, err := parser.LoadJSONProfile(*jsonProfile)
+	if err != nil {
+		fmt.Printf("❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Build the forced parser, if any, and the detection options
+	logParser, parserOpts, err := buildParser(*parserName, *grokExpr, grokPatterns, profile, *logfmtKeys)
+	if err != nil {
+		fmt.Printf("❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Build field and query filters
+	filter, err := buildFilter(fieldFilters, *query, *tz)
+	if err != nil {
+		fmt.Printf("❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Choose the reporter up front: it decides which entries must be kept in memory
+	var rep reporter.Reporter
+	var csvRep *reporter.CSVReporter
+	retention, retainLimit := analyzer.RetainAll, 0
+	switch *format {
+	case "json":
+		rep = &reporter.JSONReporter{SummaryOnly: *summaryOnly, ShowLocation: *showLocation}
+	case "csv":
+		csvRep = &reporter.CSVReporter{Columns: csvColumns, SummaryOnly: *summaryOnly}
+		rep = csvRep
+	default:
+		tableRep := &reporter.TableReporter{SummaryOnly: *summaryOnly, ShowLocation: *showLocation}
+		rep = tableRep
+		retention, retainLimit = analyzer.RetainFirstN, tableRep.MaxEntries()
+		if beforeN > 0 || afterN > 0 {
+			// Context previews keep reading order rather than the earliest entries
+			retention = analyzer.RetainHead
+		}
+	}
+	if *summaryOnly {
+		retention = analyzer.RetainNone
+	}
+
 	// Create analyzer config
 	config := &analyzer.Config{
-		Workers:    *workers,
-		Level:      minLevel,
-		Pattern:    *pattern,
-		AutoDetect: true,
+		Workers:        *workers,
+		Level:          minLevel,
+		Pattern:        *pattern,
+		Matcher:        m,
+		StartTime:      startT,
+		EndTime:        endT,
+		Filter:         filter,
+		Multiline:      *multiline,
+		MultilineStart: startRe,
+		Parser:         logParser,
+		ParserOptions:  parserOpts,
+		Retention:      retention,
+		RetainLimit:    retainLimit,
+		SkipTemplates:  *format == "csv" || (*format == "table" && *topErrors == 0),
+		Before:         beforeN,
+		After:          afterN,
+	}
+
+	// Continue from the previous run's checkpoints
+	var store *checkpoint.Store
+	if *incremental {
+		store, err = loadState(*statePath, "analyze")
+		if err != nil {
+			fmt.Printf("❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+		config.Checkpoints = store
+	}
+
+	// Keep rejected lines instead of dropping them
+	deadLetterFile, err := createOptional(*deadLetter)
+	if err != nil {
+		fmt.Printf("❌ Failed to create dead-letter file: %v\n", err)
+		os.Exit(1)
+	}
+	if deadLetterFile != nil {
+		defer deadLetterFile.Close()
+		config.DeadLetter = deadLetterFile
 	}
 
 	// Create analyzer
@@ -103,14 +262,7 @@ func handleAnalyze() {
 	fmt.Println("🚀 Starting analysis...")
 	startTime := time.Now()
 
-	var err error
-	if *file != "" {
-		err = a.AnalyzeFile(*file)
-	} else {
-		err = a.AnalyzeDirectory(*dir)
-	}
-
-	if err != nil {
+	if err := runAnalysis(a, *file, *dir, *rotated); err != nil {
 		fmt.Printf("❌ Error: %v\n", err)
 		os.Exit(1)
 	}
@@ -138,12 +290,14 @@ func handleAnalyze() {
 	}
 
 	// Generate report
-	var rep reporter.Reporter
-	switch *format {
-	case "json":
-		rep = reporter.GetReporter(reporter.JSONFormat)
-	default:
-		rep = reporter.GetReporter(reporter.TableFormat)
+	if csvRep != nil && *csvSummary != "" {
+		f, err := os.Create(*csvSummary)
+		if err != nil {
+			fmt.Printf("❌ Failed to create CSV summary file: %v\n", err)
+			os.Exit(1)
+		}
+		defer f.Close()
+		csvRep.SummaryWriter = f
 	}
 
 	if err := rep.Report(entries, stats, writer); err != nil {
@@ -160,30 +314,83 @@ func handleAnalyze() {
 	if *format == "table" && len(stats.SourceCounts) > 1 {
 		reporter.PrintSourceBreakdown(stats, writer)
 	}
+
+	// Print parse quality
+	if *format == "table" {
+		reporter.PrintParseQuality(stats, writer)
+	}
+
+	// Record progress only once the report is out
+	if store != nil {
+		if err := store.Save(); err != nil {
+			fmt.Printf("❌ Failed to save state: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	if *strict {
+		if err := checkParseQuality(stats, *strictThreshold); err != nil {
+			fmt.Printf("❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
 }
 
 func handleWatch() {
 	// Define flags
 	fs := flag.NewFlagSet("watch", flag.ExitOnError)
-	file := fs.String("file", "", "Log file to watch")
+	var files, dirs stringList
+	fs.Var(&files, "file", "Log file or glob pattern to watch (repeatable; paths may also follow the flags)")
+	fs.Var(&dirs, "dir", "Directory to watch for live log files, including new ones (repeatable)")
 	pattern := fs.String("pattern", "", "Pattern to filter for")
+	regex := fs.Bool("regex", false, "Treat --pattern as a regular expression")
+	glob := fs.Bool("glob", false, "Treat --pattern as a glob (*, ?, [abc]) matching the whole message, e.g. \"*timeout*\"")
+	ignoreCase := fs.Bool("ignore-case", false, "Case-insensitive pattern matching")
 	level := fs.String("level", "", "Minimum log level to show")
 	interval := fs.Duration("interval", 1*time.Second, "Check interval")
 	showAll := fs.Bool("all", false, "Show all existing entries (not just new ones)")
+	resume := fs.Bool("resume", false, "Continue each file where the previous --resume run stopped, tracked in the state file")
+	statePath := fs.String("state", "", "State file for --resume (default: watch.json in the user cache directory)")
+	reorder := fs.Duration("reorder", 500*time.Millisecond, "Hold entries this long to interleave files in timestamp order (0 to disable)")
+	var alertQueries stringList
+	fs.Var(&alertQueries, "alert", "Raise an alert on every entry matching a --query expression, e.g. 'level=FATAL' (repeatable)")
+	alertRules := fs.String("alert-rules", "", "JSON file of alert rules: thresholds, absence, for-duration and cooldown")
+	alertLog := fs.String("alert-log", "", "Write alerts to a file, one JSON object per line")
+	statsEvery := fs.Duration("stats-every", 0, "Print 1m/5m/15m counts per level and source, the rate and new error templates at this interval, and a summary on exit")
+	after := fs.Int("A", 0, "Show N entries after each match as context")
+	before := fs.Int("B", 0, "Show N entries before each match as context")
+	around := fs.Int("C", 0, "Show N entries before and after each match as context (-A/-B override)")
+	since := fs.String("since", "", "Only entries at or after this time (RFC3339, \"1h\", \"yesterday\", \"last monday 09:00\")")
+	until := fs.String("until", "", "Only entries at or before this time (same forms as --since)")
+	tz := fs.String("tz", "UTC", "Timezone for --since/--until without an offset (UTC, Local, +05:30, Europe/Berlin); naive log timestamps are read as UTC")
+	multiline := fs.Bool("multiline", true, "Fold stack traces and continuation lines into the preceding entry")
+	multilineStart := fs.String("multiline-start", "", "Regex matching the first line of each entry (overrides multiline heuristics)")
+	jsonProfile := fs.String("json-profile", "default", "JSON key mapping: default, pino, bunyan, zap, ecs, gcp, or a profile file")
+	logfmtKeys := fs.String("logfmt-keys", "", "Extra logfmt keys for the entry attributes, e.g. \"time=at,level=severity,msg=text\"")
+	parserName := fs.String("parser", "auto", "Parser: auto (sticky per-file detection) or "+strings.Join(parser.Names(), ", "))
+	grokExpr := fs.String("grok", "", "Grok expression, forced with --parser grok or detected alongside the others, e.g. '%{TIMESTAMP_ISO8601:ts} %{LOGLEVEL:level} %{GREEDYDATA:msg}'")
+	var grokPatterns stringList
+	fs.Var(&grokPatterns, "grok-patterns", "File of extra grok patterns, one \"NAME regex\" per line (repeatable)")
 
 	fs.Parse(os.Args[2:])
 
 	// Validate
-	if *file == "" {
-		fmt.Println("Error: --file must be specified")
+	paths := append(append(files, dirs...), fs.Args()...)
+	if len(paths) == 0 {
+		fmt.Println("Error: --file or --dir must be specified")
 		fs.PrintDefaults()
 		os.Exit(1)
 	}
 
-	// Check if file exists
-	if _, err := os.Stat(*file); os.IsNotExist(err) {
-		fmt.Printf("❌ File does not exist: %s\n", *file)
-		os.Exit(1)
+	// Check that named files exist; globs and directories may fill up later
+	for _, path := range paths {
+		if strings.ContainsAny(path, "*?[") {
+			continue
+		}
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			fmt.Printf("❌ File does not exist: %s\n", path)
+			os.Exit(1)
+		}
 	}
 
 	printBanner()
@@ -194,13 +401,91 @@ func handleWatch() {
 		minLevel = models.ParseLogLevel(strings.ToUpper(*level))
 	}
 
+	// Compile pattern
+	m, err := buildMatcher(*pattern, *regex, *glob, *ignoreCase)
+	if err != nil {
+		fmt.Printf("❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Resolve time range
+	startT, endT, err := parseTimeRange(*since, *until, *tz)
+	if err != nil {
+		fmt.Printf("❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Resolve context lines
+	beforeN, afterN, err := contextLines(fs, *before, *after, *around)
+	if err != nil {
+		fmt.Printf("❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Compile multiline start pattern
+	startRe, err := compileOptionalRegex(*multilineStart)
+	if err != nil {
+		fmt.Printf("❌ Error: invalid --multiline-start: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Resolve JSON key mapping profile
+	profile, err := parser.LoadJSONProfile(*jsonProfile)
+	if err != nil {
+		fmt.Printf("❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Build the forced parser, if any, and the detection options
+	logParser, parserOpts, err := buildParser(*parserName, *grokExpr, grokPatterns, profile, *logfmtKeys)
+	if err != nil {
+		fmt.Printf("❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+
 	// Create watcher config
 	config := &watcher.Config{
-		FilePath: *file,
-		Pattern:  *pattern,
-		MinLevel: minLevel,
-		Interval: *interval,
-		ShowAll:  *showAll,
+		Paths:          paths,
+		Pattern:        *pattern,
+		Matcher:        m,
+		MinLevel:       minLevel,
+		StartTime:      startT,
+		EndTime:        endT,
+		Interval:       *interval,
+		ShowAll:        *showAll,
+		Reorder:        *reorder,
+		Multiline:      *multiline,
+		MultilineStart: startRe,
+		Parser:         logParser,
+		ParserOptions:  parserOpts,
+		Before:         beforeN,
+		After:          afterN,
+		StatsEvery:     *statsEvery,
+	}
+
+	// Continue from the previous run's checkpoints
+	if *resume {
+		config.Checkpoints, err = loadState(*statePath, "watch")
+		if err != nil {
+			fmt.Printf("❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	// Load alert rules and open the alert log
+	config.Alerts, err = buildAlerts(alertQueries, *alertRules, *tz)
+	if err != nil {
+		fmt.Printf("❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+	alertLogFile, err := createOptional(*alertLog)
+	if err != nil {
+		fmt.Printf("❌ Failed to create alert log: %v\n", err)
+		os.Exit(1)
+	}
+	if alertLogFile != nil {
+		defer alertLogFile.Close()
+		config.AlertLog = alertLogFile
 	}
 
 	// Create watcher
@@ -234,7 +519,22 @@ func handleStats() {
 	fs := flag.NewFlagSet("stats", flag.ExitOnError)
 	file := fs.String("file", "", "Single log file to analyze")
 	dir := fs.String("dir", "", "Directory containing log files")
+	rotated := fs.Bool("rotated", false, "With --file, also read its rotated siblings (app.log.1, app.log.2.gz, ...)")
 	workers := fs.Int("workers", 4, "Number of concurrent workers")
+	since := fs.String("since", "", "Only entries at or after this time (RFC3339, \"1h\", \"yesterday\", \"last monday 09:00\")")
+	until := fs.String("until", "", "Only entries at or before this time (same forms as --since)")
+	tz := fs.String("tz", "UTC", "Timezone for --since/--until without an offset (UTC, Local, +05:30, Europe/Berlin); naive log timestamps are read as UTC")
+	multiline := fs.Bool("multiline", true, "Fold stack traces and continuation lines into the preceding entry")
+	multilineStart := fs.String("multiline-start", "", "Regex matching the first line of each entry (overrides multiline heuristics)")
+	jsonProfile := fs.String("json-profile", "default", "JSON key mapping: default, pino, bunyan, zap, ecs, gcp, or a profile file")
+	logfmtKeys := fs.String("logfmt-keys", "", "Extra logfmt keys for the entry attributes, e.g. \"time=at,level=severity,msg=text\"")
+	parserName := fs.String("parser", "auto", "Parser: auto (sticky per-file detection) or "+strings.Join(parser.Names(), ", "))
+	grokExpr := fs.String("grok", "", "Grok expression, forced with --parser grok or detected alongside the others, e.g. '%{TIMESTAMP_ISO8601:ts} %{LOGLEVEL:level} %{GREEDYDATA:msg}'")
+	var grokPatterns stringList
+	fs.Var(&grokPatterns, "grok-patterns", "File of extra grok patterns, one \"NAME regex\" per line (repeatable)")
+	deadLetter := fs.String("dead-letter", "", "Write lines no parser accepted, or only plain text did, to this file, one JSON object per line")
+	strict := fs.Bool("strict", false, "Fail the run when the share of unparseable, mismatched or degraded lines exceeds --strict-threshold")
+	strictThreshold := fs.Float64("strict-threshold", 0.01, "Highest tolerated share of bad lines for --strict, from 0 to 1")
 
 	fs.Parse(os.Args[2:])
 
@@ -247,10 +547,56 @@ func handleStats() {
 
 	printBanner()
 
-	// Create analyzer config
+	// Resolve time range
+	startT, endT, err := parseTimeRange(*since, *until, *tz)
+	if err != nil {
+		fmt.Printf("❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Compile multiline start pattern
+	startRe, err := compileOptionalRegex(*multilineStart)
+	if err != nil {
+		fmt.Printf("❌ Error: invalid --multiline-start: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Resolve JSON key mapping profile
+	profile, err := parser.LoadJSONProfile(*jsonProfile)
+	if err != nil {
+		fmt.Printf("❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Build the forced parser, if any, and the detection options
+	logParser, parserOpts, err := buildParser(*parserName, *grokExpr, grokPatterns, profile, *logfmtKeys)
+	if err != nil {
+		fmt.Printf("❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Create analyzer config; stats never needs entries, so memory stays constant
 	config := &analyzer.Config{
-		Workers:    *workers,
-		AutoDetect: true,
+		Workers:        *workers,
+		StartTime:      startT,
+		EndTime:        endT,
+		Multiline:      *multiline,
+		MultilineStart: startRe,
+		Parser:         logParser,
+		ParserOptions:  parserOpts,
+		Retention:      analyzer.RetainNone,
+		SkipTemplates:  true,
+	}
+
+	// Keep rejected lines instead of dropping them
+	deadLetterFile, err := createOptional(*deadLetter)
+	if err != nil {
+		fmt.Printf("❌ Failed to create dead-letter file: %v\n", err)
+		os.Exit(1)
+	}
+	if deadLetterFile != nil {
+		defer deadLetterFile.Close()
+		config.DeadLetter = deadLetterFile
 	}
 
 	// Create analyzer
@@ -259,14 +605,7 @@ func handleStats() {
 	// Analyze
 	fmt.Println("📊 Gathering statistics...")
 
-	var err error
-	if *file != "" {
-		err = a.AnalyzeFile(*file)
-	} else {
-		err = a.AnalyzeDirectory(*dir)
-	}
-
-	if err != nil {
+	if err := runAnalysis(a, *file, *dir, *rotated); err != nil {
 		fmt.Printf("❌ Error: %v\n", err)
 		os.Exit(1)
 	}
@@ -275,6 +614,288 @@ func handleStats() {
 	stats := a.GetResults().GetStats()
 	fmt.Println(stats.Summary())
 	reporter.PrintSourceBreakdown(stats, os.Stdout)
+	reporter.PrintParseQuality(stats, os.Stdout)
+
+	if *strict {
+		if err := checkParseQuality(stats, *strictThreshold); err != nil {
+			fmt.Printf("❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+}
+
+// checkParseQuality fails when more than threshold of the records were
+// unparseable or parsed with problems (--strict)
+func checkParseQuality(stats *models.Statistics, threshold float64) error {
+	quality := stats.TotalParseQuality()
+	if ratio := quality.BadRatio(); ratio > threshold {
+		return fmt.Errorf("strict mode: %.2f%% of %d lines were unparseable, mismatched or degraded (threshold %.2f%%)",
+			ratio*100, quality.Records, threshold*100)
+	}
+	return nil
+}
+
+// contextLines resolves -B, -A and -C into entries of context before and
+// after each match; -B and -A take precedence over -C when given, even as 0
+func contextLines(fs *flag.FlagSet, before, after, around int) (int, int, error) {
+	if before < 0 || after < 0 || around < 0 {
+		return 0, 0, fmt.Errorf("context lines (-A, -B, -C) must not be negative")
+	}
+	set := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) { set[f.Name] = true })
+	if !set["B"] {
+		before = around
+	}
+	if !set["A"] {
+		after = around
+	}
+	return before, after, nil
+}
+
+// loadState loads the checkpoint state file of a command: the --state
+// path, or <command>.json in the user cache directory
+func loadState(path, command string) (*checkpoint.Store, error) {
+	if path == "" {
+		dir, err := os.UserCacheDir()
+		if err != nil {
+			return nil, fmt.Errorf("no cache directory for the state file, use --state: %w", err)
+		}
+		path = filepath.Join(dir, "loganalyzer", command+".json")
+	}
+	return checkpoint.Load(path)
+}
+
+// createOptional creates a file for an optional path flag, returning nil
+// for an empty flag
+func createOptional(path string) (*os.File, error) {
+	if path == "" {
+		return nil, nil
+	}
+	return os.Create(path)
+}
+
+// buildMatcher compiles the --pattern flag according to the matching flags
+func buildMatcher(pattern string, regex, glob, ignoreCase bool) (matcher.Matcher, error) {
+	if pattern == "" {
+		return nil, nil
+	}
+	if regex && glob {
+		return nil, fmt.Errorf("--regex and --glob are mutually exclusive")
+	}
+
+	mode := matcher.Literal
+	switch {
+	case regex:
+		mode = matcher.Regex
+	case glob:
+		mode = matcher.Glob
+	}
+
+	return matcher.Compile(pattern, mode, ignoreCase)
+}
+
+// stringList is a repeatable string flag
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ", ")
+}
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+// buildFilter turns key=value flags and the --query expression into an AND-ed filter
+func buildFilter(fieldSpecs []string, query, tz string) (analyzer.FilterFunc, error) {
+	var filters []analyzer.FilterFunc
+	for _, spec := range fieldSpecs {
+		key, value, ok := strings.Cut(spec, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid --field %q, expected key=value", spec)
+		}
+		filters = append(filters, analyzer.FieldFilter(key, value))
+	}
+
+	if query != "" {
+		loc, err := timeexpr.LoadLocation(tz)
+		if err != nil {
+			return nil, err
+		}
+		q, err := analyzer.ParseQuery(query, loc)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, q)
+	}
+
+	if len(filters) == 0 {
+		return nil, nil
+	}
+	return analyzer.CombineFilters(filters...), nil
+}
+
+// buildAlerts builds the alert engine from --alert queries and the
+// --alert-rules file; nil when there are no rules
+func buildAlerts(queries []string, rulesPath, tz string) (*alert.Engine, error) {
+	if len(queries) == 0 && rulesPath == "" {
+		return nil, nil
+	}
+	loc, err := timeexpr.LoadLocation(tz)
+	if err != nil {
+		return nil, err
+	}
+
+	var rules []*alert.Rule
+	if rulesPath != "" {
+		if rules, err = alert.LoadRules(rulesPath, loc); err != nil {
+			return nil, err
+		}
+	}
+	for _, query := range queries {
+		rule, err := alert.QueryRule(query, loc)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --alert: %w", err)
+		}
+		rules = append(rules, rule)
+	}
+	return alert.NewEngine(rules, time.Now()), nil
+}
+
+// buildParser resolves --parser from the parser registry, returning a nil
+// parser when parsers are detected per file. The options configure both
+// the forced parser and the detection candidates; a --grok expression
+// without --parser makes grok one of the candidates.
+func buildParser(name, grokExpr string, grokPatternFiles []string, profile *parser.JSONProfile, logfmtKeys string) (parser.LogParser, parser.Options, error) {
+	opts := parser.Options{JSONProfile: profile, GrokExpression: grokExpr}
+	keys, err := parser.ParseKeyAliases(logfmtKeys)
+	if err != nil {
+		return nil, opts, fmt.Errorf("invalid --logfmt-keys: %w", err)
+	}
+	opts.LogfmtKeys = keys
+	if len(grokPatternFiles) > 0 {
+		patterns, err := parser.LoadGrokPatterns(grokPatternFiles...)
+		if err != nil {
+			return nil, opts, err
+		}
+		opts.GrokPatterns = patterns
+	}
+
+	if name == "" || strings.EqualFold(name, "auto") {
+		return nil, opts, nil
+	}
+	p, err := parser.New(name, opts)
+	if err != nil {
+		return nil, opts, err
+	}
+	return p, opts, nil
+}
+
+// compileOptionalRegex compiles a regex flag, returning nil for an empty flag
+func compileOptionalRegex(expr string) (*regexp.Regexp, error) {
+	if expr == "" {
+		return nil, nil
+	}
+	return regexp.Compile(expr)
+}
+
+// runAnalysis analyzes --file (optionally with its rotation set) or --dir
+func runAnalysis(a *analyzer.Analyzer, file, dir string, rotated bool) error {
+	if file == "" {
+		return a.AnalyzeDirectory(dir)
+	}
+	if !rotated {
+		return a.AnalyzeFile(file)
+	}
+
+	files, err := input.RotationSet(file)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("🔁 Rotation set: %d files (%s … %s)\n",
+		len(files), filepath.Base(files[0]), filepath.Base(files[len(files)-1]))
+	return a.AnalyzeFiles(files)
+}
+
+// parseTimeRange resolves the --since/--until/--tz flags
+func parseTimeRange(since, until, tz string) (time.Time, time.Time, error) {
+	loc, err := timeexpr.LoadLocation(tz)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	return timeexpr.ParseRange(since, until, time.Now(), loc)
+}
+
+func handleShow() {
+	// Define flags
+	fs := flag.NewFlagSet("show", flag.ExitOnError)
+	context := fs.Int("context", 5, "Lines of context before and after the entry")
+	multiline := fs.Bool("multiline", true, "Treat stack traces and continuation lines as part of the entry")
+	multilineStart := fs.String("multiline-start", "", "Regex matching the first line of each entry (overrides multiline heuristics)")
+
+	fs.Parse(os.Args[2:])
+
+	// Validate
+	if fs.NArg() != 1 {
+		fmt.Println("Error: expected one <file>:<line> argument")
+		fs.PrintDefaults()
+		os.Exit(1)
+	}
+	path, line, err := parseLocation(fs.Arg(0))
+	if err != nil {
+		fmt.Printf("❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+	if *context < 0 {
+		fmt.Println("❌ Error: --context must not be negative")
+		os.Exit(1)
+	}
+
+	// Compile multiline start pattern
+	startRe, err := compileOptionalRegex(*multilineStart)
+	if err != nil {
+		fmt.Printf("❌ Error: invalid --multiline-start: %v\n", err)
+		os.Exit(1)
+	}
+
+	excerpt, err := analyzer.Locate(path, line, *context, *multiline, startRe)
+	if err != nil {
+		fmt.Printf("❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if excerpt.Start == excerpt.End {
+		fmt.Printf("📍 %s:%d\n", excerpt.Path, excerpt.Start)
+	} else {
+		fmt.Printf("📍 %s:%d-%d\n", excerpt.Path, excerpt.Start, excerpt.End)
+	}
+	fmt.Println(color.New(color.FgCyan).Sprint("───────────────────────────────────────────────"))
+
+	for _, l := range excerpt.Lines {
+		marker := " "
+		if l.Line == excerpt.Target {
+			marker = ">"
+		}
+		gutter := fmt.Sprintf("%s %6d │", marker, l.Line)
+		if l.InEntry {
+			fmt.Printf("%s %s\n", color.New(color.FgYellow).Sprint(gutter), l.Text)
+		} else {
+			fmt.Printf("%s %s\n", color.New(color.FgHiBlack).Sprint(gutter), color.New(color.FgHiBlack).Sprint(l.Text))
+		}
+	}
+}
+
+// parseLocation splits a "file:line" argument
+func parseLocation(arg string) (string, int, error) {
+	i := strings.LastIndex(arg, ":")
+	if i <= 0 {
+		return "", 0, fmt.Errorf("expected <file>:<line>, got %q", arg)
+	}
+	line, err := strconv.Atoi(arg[i+1:])
+	if err != nil || line <= 0 {
+		return "", 0, fmt.Errorf("invalid line number in %q", arg)
+	}
+	return arg[:i], line, nil
 }
 
 func printBanner() {
@@ -286,32 +907,100 @@ func printUsage() {
 	fmt.Println("Usage: loganalyzer <command> [options]")
 	fmt.Println("\nCommands:")
 	fmt.Println("  analyze    Analyze log files")
-	fmt.Println("  watch      Watch a log file in real-time")
+	fmt.Println("  watch      Watch log files in real-time")
 	fmt.Println("  stats      Show statistics for log files")
+	fmt.Println("  show       Show the entry at file:line with surrounding lines")
 	fmt.Println("  help       Show this help message")
 	fmt.Println("  version    Show version information")
 
 	fmt.Println("\nAnalyze Options:")
 	fmt.Println("  --file <path>        Single log file to analyze")
 	fmt.Println("  --dir <path>         Directory containing log files")
+	fmt.Println("  --rotated            With --file, include rotated/compressed siblings")
 	fmt.Println("  --level <level>      Minimum log level (DEBUG, INFO, WARN, ERROR, FATAL)")
 	fmt.Println("  --pattern <string>   Pattern to search for")
+	fmt.Println("  --regex              Treat pattern as a regular expression")
+	fmt.Println("  --glob               Treat pattern as a glob (*, ?, [abc])")
+	fmt.Println("  --ignore-case        Case-insensitive pattern matching")
 	fmt.Println("  --workers <num>      Number of concurrent workers (default: 4)")
-	fmt.Println("  --format <format>    Output format: table, json (default: table)")
+	fmt.Println("  --format <format>    Output format: table, json, csv (default: table)")
 	fmt.Println("  --output <path>      Output file (default: stdout)")
+	fmt.Println("  --columns <list>     CSV columns: timestamp,level,source,message,raw,path,line,offset,location,kind,fields.<name>,fields.*")
+	fmt.Println("  --csv-summary <path> Write level/source counts as CSV")
+	fmt.Println("  --summary-only       Statistics only, no entries (constant memory)")
 	fmt.Println("  --top-errors <num>   Show top N error patterns")
+	fmt.Println("  --show-location      Show the path:line of each entry")
+	fmt.Println("  -A/-B/-C <num>       Show N entries after/before/around each match as context")
+	fmt.Println("  --field <key=value>  Only entries whose field equals value (repeatable)")
+	fmt.Println("  --query <expr>       Filter expression over level, source, msg, raw, time, fields.*")
+	fmt.Println("  --since <time>       Only entries at or after time (RFC3339, 1h, yesterday, last monday 09:00)")
+	fmt.Println("  --until <time>       Only entries at or before time")
+	fmt.Println("  --tz <zone>          Timezone for --since/--until (default: UTC, matching naive log timestamps)")
+	fmt.Println("  --multiline          Fold stack traces into the preceding entry (default: true)")
+	fmt.Println("  --multiline-start <re> Regex for the first line of each entry")
+	fmt.Println("  --json-profile <name> JSON key mapping: default, pino, bunyan, zap, ecs, gcp, or a file")
+	fmt.Println("  --logfmt-keys <list> logfmt keys for time/level/msg, e.g. time=at,level=severity")
+	fmt.Println("  --parser <name>      Force a parser: " + strings.Join(parser.Names(), ", ") + " (default: auto, locked per file)")
+	fmt.Println("  --grok <expr>        Grok expression (forced with --parser grok, else detected)")
+	fmt.Println("  --grok-patterns <file> Extra grok pattern definitions (repeatable)")
+	fmt.Println("  --dead-letter <file> Write lines no parser accepted to a file (JSON lines)")
+	fmt.Println("  --strict             Fail when bad lines exceed --strict-threshold (default: 0.01)")
+	fmt.Println("  --incremental        Only analyze lines added since the last --incremental run")
+	fmt.Println("  --state <path>       State file for --incremental (default: in the user cache dir)")
 
 	fmt.Println("\nWatch Options:")
-	fmt.Println("  --file <path>        Log file to watch")
+	fmt.Println("  loganalyzer watch [options] [paths...]")
+	fmt.Println("  --file <path|glob>   Log file or glob to watch (repeatable)")
+	fmt.Println("  --dir <path>         Directory to watch, picking up new log files (repeatable)")
 	fmt.Println("  --pattern <string>   Pattern to filter for")
+	fmt.Println("  --regex              Treat pattern as a regular expression")
+	fmt.Println("  --glob               Treat pattern as a glob (*, ?, [abc])")
+	fmt.Println("  --ignore-case        Case-insensitive pattern matching")
 	fmt.Println("  --level <level>      Minimum log level to show")
 	fmt.Println("  --interval <dur>     Check interval (default: 1s)")
 	fmt.Println("  --all                Show all existing entries")
+	fmt.Println("  --reorder <dur>      Interleave files in timestamp order within this window (default: 500ms)")
+	fmt.Println("  --stats-every <dur>  Print sliding-window stats at this interval and a summary on exit")
+	fmt.Println("  --alert <query>      Alert on every entry matching a --query expression (repeatable)")
+	fmt.Println("  --alert-rules <file> JSON alert rules: thresholds, absence, for-duration, cooldown")
+	fmt.Println("  --alert-log <file>   Write alerts to a file (JSON lines)")
+	fmt.Println("  --resume             Continue where the last --resume run stopped")
+	fmt.Println("  --state <path>       State file for --resume (default: in the user cache dir)")
+	fmt.Println("  -A/-B/-C <num>       Show N entries after/before/around each match as context")
+	fmt.Println("  --since <time>       Only entries at or after time (RFC3339, 1h, yesterday, last monday 09:00)")
+	fmt.Println("  --until <time>       Only entries at or before time")
+	fmt.Println("  --tz <zone>          Timezone for --since/--until (default: UTC, matching naive log timestamps)")
+	fmt.Println("  --multiline          Fold stack traces into the preceding entry (default: true)")
+	fmt.Println("  --multiline-start <re> Regex for the first line of each entry")
+	fmt.Println("  --json-profile <name> JSON key mapping: default, pino, bunyan, zap, ecs, gcp, or a file")
+	fmt.Println("  --logfmt-keys <list> logfmt keys for time/level/msg, e.g. time=at,level=severity")
+	fmt.Println("  --parser <name>      Force a parser: " + strings.Join(parser.Names(), ", ") + " (default: auto, locked per file)")
+	fmt.Println("  --grok <expr>        Grok expression (forced with --parser grok, else detected)")
+	fmt.Println("  --grok-patterns <file> Extra grok pattern definitions (repeatable)")
 
 	fmt.Println("\nStats Options:")
 	fmt.Println("  --file <path>        Single log file to analyze")
 	fmt.Println("  --dir <path>         Directory containing log files")
+	fmt.Println("  --rotated            With --file, include rotated/compressed siblings")
 	fmt.Println("  --workers <num>      Number of concurrent workers (default: 4)")
+	fmt.Println("  --since <time>       Only entries at or after time (RFC3339, 1h, yesterday, last monday 09:00)")
+	fmt.Println("  --until <time>       Only entries at or before time")
+	fmt.Println("  --tz <zone>          Timezone for --since/--until (default: UTC, matching naive log timestamps)")
+	fmt.Println("  --multiline          Fold stack traces into the preceding entry (default: true)")
+	fmt.Println("  --multiline-start <re> Regex for the first line of each entry")
+	fmt.Println("  --json-profile <name> JSON key mapping: default, pino, bunyan, zap, ecs, gcp, or a file")
+	fmt.Println("  --logfmt-keys <list> logfmt keys for time/level/msg, e.g. time=at,level=severity")
+	fmt.Println("  --parser <name>      Force a parser: " + strings.Join(parser.Names(), ", ") + " (default: auto, locked per file)")
+	fmt.Println("  --grok <expr>        Grok expression (forced with --parser grok, else detected)")
+	fmt.Println("  --grok-patterns <file> Extra grok pattern definitions (repeatable)")
+	fmt.Println("  --dead-letter <file> Write lines no parser accepted to a file (JSON lines)")
+	fmt.Println("  --strict             Fail when bad lines exceed --strict-threshold (default: 0.01)")
+
+	fmt.Println("\nShow Options:")
+	fmt.Println("  loganalyzer show [options] <file>:<line>")
+	fmt.Println("  --context <num>      Lines of context around the entry (default: 5)")
+	fmt.Println("  --multiline          Show the whole multiline entry (default: true)")
+	fmt.Println("  --multiline-start <re> Regex for the first line of each entry")
 
 	fmt.Println("\nExamples:")
 	fmt.Println("  # Analyze a single file for errors")
@@ -320,12 +1009,48 @@ func printUsage() {
 	fmt.Println("  # Analyze directory with pattern matching")
 	fmt.Println("  loganalyzer analyze --dir ./logs --pattern \"database\" --workers 8")
 	fmt.Println()
+	fmt.Println("  # Search with a regular expression")
+	fmt.Println("  loganalyzer analyze --file app.log --pattern \"timeout after \\d+ms\" --regex")
+	fmt.Println()
+	fmt.Println("  # Errors with the 3 entries before and after each")
+	fmt.Println("  loganalyzer analyze --file app.log --pattern \"timeout\" -C 3")
+	fmt.Println()
 	fmt.Println("  # Watch file in real-time")
 	fmt.Println("  loganalyzer watch --file app.log --level WARN")
 	fmt.Println()
+	fmt.Println("  # Follow a whole service, including files created later")
+	fmt.Println("  loganalyzer watch --dir /var/log/myapp --level ERROR")
+	fmt.Println()
+	fmt.Println("  # Live error rates every 30 seconds")
+	fmt.Println("  loganalyzer watch --file app.log --stats-every 30s")
+	fmt.Println()
+	fmt.Println("  # Alert on fatal errors and on the rules in alerts.json")
+	fmt.Println("  loganalyzer watch --dir /var/log/myapp --alert 'level=FATAL' --alert-rules alerts.json --alert-log alerts.jsonl")
+	fmt.Println()
 	fmt.Println("  # Generate JSON report")
 	fmt.Println("  loganalyzer analyze --dir ./logs --format json --output report.json")
 	fmt.Println()
+	fmt.Println("  # Query structured fields")
+	fmt.Println("  loganalyzer analyze --dir ./logs --query 'level>=WARN and fields.status>=500 and not fields.path=\"/health\"'")
+	fmt.Println()
+	fmt.Println("  # Export errors as CSV with a separate summary")
+	fmt.Println("  loganalyzer analyze --dir ./logs --level ERROR --format csv --output errors.csv --csv-summary counts.csv")
+	fmt.Println()
+	fmt.Println("  # Errors since yesterday morning, in UTC")
+	fmt.Println("  loganalyzer analyze --dir ./logs --level ERROR --since \"yesterday 09:00\" --tz UTC")
+	fmt.Println()
+	fmt.Println("  # Analyze a whole retention window (app.log, app.log.1, app.log.2.gz, ...)")
+	fmt.Println("  loganalyzer analyze --file /var/log/app.log --rotated")
+	fmt.Println()
+	fmt.Println("  # Read pino logs (epoch ms timestamps, numeric levels)")
+	fmt.Println("  loganalyzer analyze --file service.log --json-profile pino")
+	fmt.Println()
+	fmt.Println("  # Parse a custom text format with grok")
+	fmt.Println("  loganalyzer analyze --file app.log --parser grok --grok '%{TIMESTAMP_ISO8601:ts} %{LOGLEVEL:level} \\[%{DATA:thread}\\] %{GREEDYDATA:msg}'")
+	fmt.Println()
+	fmt.Println("  # Hourly cron job: only lines written since the last run")
+	fmt.Println("  loganalyzer analyze --file /var/log/app.log --incremental --level ERROR")
+	fmt.Println()
 	fmt.Println("  # Show statistics")
 	fmt.Println("  loganalyzer stats --dir ./logs")
 	fmt.Println()
@@ -0,0 +1,319 @@
+package alert
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aadithyaa9/loganalyzer/internal/models"
+	"github.com/aadithyaa9/loganalyzer/internal/templates"
+)
+
+// Engine evaluates rules against a live stream of entries, timed by when
+// the entries are read. It is not thread-safe: entries and clock ticks
+// come from one loop.
+type Engine struct {
+	rules []*ruleState
+}
+
+// ruleState is the state of one rule
+type ruleState struct {
+	rule   *Rule
+	groups map[string]*group // By source with PerSource, else one group ""
+}
+
+// group is the state of a rule for one source, or for all of them
+type group struct {
+	source string
+
+	// Threshold rules: when the matching entries within the window were
+	// read, oldest first, and up to Samples of them kept for the alert,
+	// the earliest first
+	recent  []time.Time
+	samples []observation
+
+	// Absence rules: when the last matching entry was read
+	lastSeen  time.Time
+	lastEntry *models.LogEntry
+
+	// Threshold and absence rules: an alert fires once the condition has
+	// held since pendingSince for the rule's For, and resolves once it no
+	// longer holds; it is not raised again within the cooldown
+	pendingSince time.Time
+	firing       bool
+	lastFired    time.Time
+
+	// Instant rules: the last alert per message template, for dedup
+	fired map[string]*dedup
+}
+
+// observation is a matching entry and when it was read
+type observation struct {
+	at    time.Time
+	entry *models.LogEntry
+}
+
+// dedup tracks the alerts of one message template of an instant rule
+type dedup struct {
+	last       time.Time
+	suppressed int              // Matches dropped since the last alert
+	lastEntry  *models.LogEntry // The last match dropped
+}
+
+// NewEngine creates an engine for the rules, starting at now
+func NewEngine(rules []*Rule, now time.Time) *Engine {
+	e := &Engine{}
+	for _, rule := range rules {
+		rs := &ruleState{rule: rule, groups: make(map[string]*group)}
+		// Silence is measured from the start until a first entry is read
+		if rule.Kind() == Absence && !rule.PerSource {
+			rs.group("", now)
+		}
+		e.rules = append(e.rules, rs)
+	}
+	return e
+}
+
+// Len returns the number of rules
+func (e *Engine) Len() int {
+	return len(e.rules)
+}
+
+// Observe feeds an entry read at now to the rules, returning the alerts it raises
+func (e *Engine) Observe(entry *models.LogEntry, now time.Time) []Event {
+	var events []Event
+	for _, rs := range e.rules {
+		if !rs.rule.Match(entry) {
+			continue
+		}
+		source := ""
+		if rs.rule.PerSource {
+			source = entry.Source
+		}
+		g := rs.group(source, now)
+
+		switch rs.rule.Kind() {
+		case Instant:
+			if event, ok := rs.instant(g, entry, now); ok {
+				events = append(events, event)
+			}
+			continue
+		case Threshold:
+			g.recent = append(g.recent, now)
+			if len(g.samples) < rs.rule.Samples {
+				g.samples = append(g.samples, observation{now, entry})
+			}
+		case Absence:
+			g.lastSeen = now
+			g.lastEntry = entry
+		}
+		if event, ok := rs.evaluate(g, now); ok {
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
+// ObserveBacklog feeds an entry written before the watch started, such as
+// one read with --all or when resuming. Windows and silences are measured
+// by when entries are read, so a backlog only raises per-entry alerts.
+func (e *Engine) ObserveBacklog(entry *models.LogEntry, now time.Time) []Event {
+	var events []Event
+	for _, rs := range e.rules {
+		if rs.rule.Kind() != Instant || !rs.rule.Match(entry) {
+			continue
+		}
+		source := ""
+		if rs.rule.PerSource {
+			source = entry.Source
+		}
+		if event, ok := rs.instant(rs.group(source, now), entry, now); ok {
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
+// Evaluate advances the rules to now, returning the alerts raised or
+// resolved by the passing of time: windows emptying, silences and
+// for-durations elapsing, and cooldowns ending with duplicates dropped
+func (e *Engine) Evaluate(now time.Time) []Event {
+	var events []Event
+	for _, rs := range e.rules {
+		for _, g := range rs.groups {
+			if rs.rule.Kind() == Instant {
+				for key, d := range g.fired {
+					if now.Sub(d.last) < rs.rule.Cooldown {
+						continue
+					}
+					if d.suppressed > 0 {
+						events = append(events, rs.duplicates(g, d, now))
+					}
+					delete(g.fired, key)
+				}
+				continue
+			}
+			if event, ok := rs.evaluate(g, now); ok {
+				events = append(events, event)
+			}
+		}
+	}
+	return events
+}
+
+// group returns the state for a source, creating it as of now
+func (rs *ruleState) group(source string, now time.Time) *group {
+	g, ok := rs.groups[source]
+	if !ok {
+		g = &group{source: source, lastSeen: now, fired: make(map[string]*dedup)}
+		rs.groups[source] = g
+	}
+	return g
+}
+
+// instant raises an alert for a matching entry, unless one was raised for
+// the same message template within the cooldown
+func (rs *ruleState) instant(g *group, entry *models.LogEntry, now time.Time) (Event, bool) {
+	key := templates.Mask(entry.Message)
+	d, ok := g.fired[key]
+	if ok && now.Sub(d.last) < rs.rule.Cooldown {
+		d.suppressed++
+		d.lastEntry = entry
+		return Event{}, false
+	}
+	if !ok {
+		d = &dedup{}
+		g.fired[key] = d
+	}
+
+	event := rs.event(g, Firing, now, "matching entry")
+	event.Count = 1
+	event.Suppressed = d.suppressed
+	event.Samples = []Sample{newSample(entry)}
+	d.last = now
+	d.suppressed = 0
+	d.lastEntry = nil
+	return event, true
+}
+
+// duplicates builds the alert reporting the matches of a message template
+// dropped during a cooldown that ended
+func (rs *ruleState) duplicates(g *group, d *dedup, now time.Time) Event {
+	event := rs.event(g, Firing, now, fmt.Sprintf("%d more matching entries during the cooldown", d.suppressed))
+	event.Count = d.suppressed
+	event.Suppressed = d.suppressed
+	event.Samples = []Sample{newSample(d.lastEntry)}
+	return event
+}
+
+// evaluate moves a threshold or absence rule's group to its state at now,
+// returning the alert raised or resolved, if any
+func (rs *ruleState) evaluate(g *group, now time.Time) (Event, bool) {
+	rule := rs.rule
+	var holds bool
+	switch rule.Kind() {
+	case Threshold:
+		// Drop the entries that left the window
+		g.recent = g.recent[expired(g.recent, now, rule.Window):]
+		drop := 0
+		for drop < len(g.samples) && now.Sub(g.samples[drop].at) >= rule.Window {
+			drop++
+		}
+		g.samples = g.samples[drop:]
+		holds = len(g.recent) > rule.Threshold
+	case Absence:
+		holds = now.Sub(g.lastSeen) >= rule.Absent
+	}
+
+	if !holds {
+		g.pendingSince = time.Time{}
+		if !g.firing {
+			return Event{}, false
+		}
+		g.firing = false
+		return rs.resolved(g, now), true
+	}
+
+	if g.pendingSince.IsZero() {
+		g.pendingSince = now
+	}
+	if g.firing || now.Sub(g.pendingSince) < rule.For {
+		return Event{}, false
+	}
+	if !g.lastFired.IsZero() && now.Sub(g.lastFired) < rule.Cooldown {
+		return Event{}, false
+	}
+	g.firing = true
+	g.lastFired = now
+	return rs.firing(g, now), true
+}
+
+// firing builds the alert of a threshold or absence rule starting to fire
+func (rs *ruleState) firing(g *group, now time.Time) Event {
+	rule := rs.rule
+	if rule.Kind() == Absence {
+		silence := now.Sub(g.lastSeen).Round(time.Second)
+		event := rs.event(g, Firing, now, fmt.Sprintf("no matching entries for %s", formatDuration(silence)))
+		if g.lastEntry != nil {
+			event.Samples = []Sample{newSample(g.lastEntry)}
+		}
+		return event
+	}
+
+	event := rs.event(g, Firing, now, fmt.Sprintf("%d matching entries in the last %s (more than %d)",
+		len(g.recent), formatDuration(rule.Window), rule.Threshold))
+	event.Count = len(g.recent)
+	for _, o := range g.samples {
+		event.Samples = append(event.Samples, newSample(o.entry))
+	}
+	return event
+}
+
+// resolved builds the alert of a threshold or absence rule that stopped firing
+func (rs *ruleState) resolved(g *group, now time.Time) Event {
+	rule := rs.rule
+	if rule.Kind() == Absence {
+		event := rs.event(g, Resolved, now, "matching entries are read again")
+		event.Count = 1
+		event.Samples = []Sample{newSample(g.lastEntry)}
+		return event
+	}
+
+	event := rs.event(g, Resolved, now, fmt.Sprintf("%d matching entries in the last %s (at most %d)",
+		len(g.recent), formatDuration(rule.Window), rule.Threshold))
+	event.Count = len(g.recent)
+	return event
+}
+
+// event creates an alert of the rule for a group
+func (rs *ruleState) event(g *group, state State, now time.Time, summary string) Event {
+	return Event{
+		Rule:    rs.rule.Name,
+		Kind:    rs.rule.Kind().String(),
+		State:   state,
+		Source:  g.source,
+		Time:    now,
+		Summary: summary,
+	}
+}
+
+// expired returns how many of the read times, oldest first, are window or
+// more before now
+func expired(times []time.Time, now time.Time, window time.Duration) int {
+	return sort.Search(len(times), func(i int) bool {
+		return now.Sub(times[i]) < window
+	})
+}
+
+// formatDuration formats a duration without trailing zero units ("1m", "1h30m")
+func formatDuration(d time.Duration) string {
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = s[:len(s)-2]
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = s[:len(s)-2]
+	}
+	return s
+}
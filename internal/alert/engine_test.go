@@ -0,0 +1,363 @@
+package alert
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/aadithyaa9/loganalyzer/internal/models"
+)
+
+// step is an input to the engine at an offset from the start: an entry
+// when message is set, otherwise a clock tick
+type step struct {
+	at      time.Duration
+	level   models.LogLevel
+	source  string
+	message string
+	backlog bool
+}
+
+// fired is the expected outcome of a step
+type fired struct {
+	state      State
+	source     string
+	count      int
+	suppressed int
+	samples    int
+}
+
+// run feeds the steps to an engine for rule and returns the events of each step
+func run(t *testing.T, rule *Rule, steps []step) [][]Event {
+	t.Helper()
+	start := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	if rule.Samples == 0 {
+		rule.Samples = DefaultSamples
+	}
+	engine := NewEngine([]*Rule{rule}, start)
+
+	result := make([][]Event, len(steps))
+	for i, s := range steps {
+		now := start.Add(s.at)
+		switch {
+		case s.message == "":
+			result[i] = engine.Evaluate(now)
+		case s.backlog:
+			result[i] = engine.ObserveBacklog(entry(s), now)
+		default:
+			result[i] = engine.Observe(entry(s), now)
+		}
+	}
+	return result
+}
+
+func entry(s step) *models.LogEntry {
+	source := s.source
+	if source == "" {
+		source = "app.log"
+	}
+	return &models.LogEntry{Level: s.level, Source: source, Message: s.message}
+}
+
+func errorsOnly(entry *models.LogEntry) bool {
+	return entry.Level >= models.ERROR && entry.Level != models.UNKNOWN
+}
+
+func TestEngine(t *testing.T) {
+	tests := []struct {
+		name  string
+		rule  Rule
+		steps []step
+		want  map[int]fired // Events by step index; other steps raise none
+	}{
+		{
+			name: "threshold fires once above the threshold and resolves as the window empties",
+			rule: Rule{Name: "burst", Match: errorsOnly, Threshold: 2, Window: time.Minute},
+			steps: []step{
+				{at: 0, level: models.ERROR, message: "a"},
+				{at: 10 * time.Second, level: models.ERROR, message: "b"},
+				{at: 20 * time.Second, level: models.INFO, message: "ignored"},
+				{at: 30 * time.Second, level: models.ERROR, message: "c"},
+				{at: 40 * time.Second, level: models.ERROR, message: "d"},
+				{at: 61 * time.Second},
+				{at: 75 * time.Second},
+			},
+			want: map[int]fired{
+				3: {state: Firing, count: 3, samples: 3},
+				6: {state: Resolved, count: 2},
+			},
+		},
+		{
+			name: "threshold waits for the for-duration",
+			rule: Rule{Name: "burst", Match: errorsOnly, Threshold: 1, Window: time.Minute, For: 30 * time.Second},
+			steps: []step{
+				{at: 0, level: models.ERROR, message: "a"},
+				{at: 5 * time.Second, level: models.ERROR, message: "b"},
+				{at: 20 * time.Second},
+				{at: 35 * time.Second},
+				{at: 50 * time.Second},
+			},
+			want: map[int]fired{
+				3: {state: Firing, count: 2, samples: 2},
+			},
+		},
+		{
+			name: "for-duration restarts when the condition clears",
+			rule: Rule{Name: "burst", Match: errorsOnly, Window: 10 * time.Second, For: 15 * time.Second},
+			steps: []step{
+				{at: 0, level: models.ERROR, message: "a"},
+				{at: 11 * time.Second},
+				{at: 12 * time.Second, level: models.ERROR, message: "b"},
+				{at: 20 * time.Second, level: models.ERROR, message: "c"},
+				{at: 26 * time.Second},
+				{at: 28 * time.Second},
+			},
+			want: map[int]fired{
+				5: {state: Firing, count: 1, samples: 1},
+			},
+		},
+		{
+			name: "cooldown holds back a threshold alert that fires again",
+			rule: Rule{Name: "burst", Match: errorsOnly, Window: 10 * time.Second, Cooldown: time.Minute},
+			steps: []step{
+				{at: 0, level: models.ERROR, message: "a"},
+				{at: 15 * time.Second},
+				{at: 20 * time.Second, level: models.ERROR, message: "b"},
+				{at: 35 * time.Second},
+				{at: 65 * time.Second, level: models.ERROR, message: "c"},
+			},
+			want: map[int]fired{
+				0: {state: Firing, count: 1, samples: 1},
+				1: {state: Resolved},
+				4: {state: Firing, count: 1, samples: 1},
+			},
+		},
+		{
+			name: "per-source thresholds are evaluated separately",
+			rule: Rule{Name: "burst", Match: errorsOnly, Threshold: 1, Window: time.Minute, PerSource: true},
+			steps: []step{
+				{at: 0, level: models.ERROR, source: "api.log", message: "a"},
+				{at: 1 * time.Second, level: models.ERROR, source: "db.log", message: "b"},
+				{at: 2 * time.Second, level: models.ERROR, source: "api.log", message: "c"},
+			},
+			want: map[int]fired{
+				2: {state: Firing, source: "api.log", count: 2, samples: 2},
+			},
+		},
+		{
+			name: "absence fires after the silence and resolves on the next entry",
+			rule: Rule{Name: "silence", Match: func(*models.LogEntry) bool { return true }, Absent: time.Minute},
+			steps: []step{
+				{at: 30 * time.Second, level: models.INFO, message: "a"},
+				{at: 80 * time.Second},
+				{at: 90 * time.Second},
+				{at: 95 * time.Second},
+				{at: 100 * time.Second, level: models.INFO, message: "b"},
+			},
+			want: map[int]fired{
+				2: {state: Firing, samples: 1},
+				4: {state: Resolved, count: 1, samples: 1},
+			},
+		},
+		{
+			name: "absence counts from the start when nothing was read",
+			rule: Rule{Name: "silence", Match: func(*models.LogEntry) bool { return true }, Absent: time.Minute},
+			steps: []step{
+				{at: 59 * time.Second},
+				{at: 60 * time.Second},
+			},
+			want: map[int]fired{
+				1: {state: Firing},
+			},
+		},
+		{
+			name: "instant rules drop repeats of a template during the cooldown",
+			rule: Rule{Name: "oom", Match: errorsOnly, Cooldown: 5 * time.Minute},
+			steps: []step{
+				{at: 0, level: models.ERROR, message: "pod 1 OOMKilled"},
+				{at: 1 * time.Second, level: models.ERROR, message: "pod 2 OOMKilled"},
+				{at: 2 * time.Second, level: models.ERROR, message: "disk full"},
+				{at: 3 * time.Second, level: models.ERROR, message: "pod 3 OOMKilled"},
+				{at: 4 * time.Second},
+				{at: 6 * time.Minute, level: models.ERROR, message: "pod 4 OOMKilled"},
+			},
+			want: map[int]fired{
+				0: {state: Firing, count: 1, samples: 1},
+				2: {state: Firing, count: 1, samples: 1},
+				5: {state: Firing, count: 1, suppressed: 2, samples: 1},
+			},
+		},
+		{
+			name: "duplicates are reported when the cooldown ends",
+			rule: Rule{Name: "oom", Match: errorsOnly, Cooldown: 5 * time.Minute},
+			steps: []step{
+				{at: 0, level: models.ERROR, message: "pod 1 OOMKilled"},
+				{at: 1 * time.Second, level: models.ERROR, message: "pod 2 OOMKilled"},
+				{at: 2 * time.Second, level: models.ERROR, message: "pod 3 OOMKilled"},
+				{at: 4 * time.Minute},
+				{at: 5 * time.Minute},
+				{at: 10 * time.Minute, level: models.ERROR, message: "pod 4 OOMKilled"},
+			},
+			want: map[int]fired{
+				0: {state: Firing, count: 1, samples: 1},
+				4: {state: Firing, count: 2, suppressed: 2, samples: 1},
+				5: {state: Firing, count: 1, samples: 1},
+			},
+		},
+		{
+			name: "a backlog raises per-entry alerts only",
+			rule: Rule{Name: "fatal", Match: errorsOnly},
+			steps: []step{
+				{at: 0, level: models.FATAL, message: "boom", backlog: true},
+			},
+			want: map[int]fired{
+				0: {state: Firing, count: 1, samples: 1},
+			},
+		},
+		{
+			name: "a backlog does not fill threshold windows",
+			rule: Rule{Name: "burst", Match: errorsOnly, Window: time.Minute},
+			steps: []step{
+				{at: 0, level: models.ERROR, message: "a", backlog: true},
+				{at: 0, level: models.ERROR, message: "b", backlog: true},
+				{at: 1 * time.Second},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := tt.rule
+			events := run(t, &rule, tt.steps)
+			for i, got := range events {
+				want, ok := tt.want[i]
+				if !ok {
+					if len(got) != 0 {
+						t.Errorf("step %d: got %d events, want none: %+v", i, len(got), got)
+					}
+					continue
+				}
+				if len(got) != 1 {
+					t.Errorf("step %d: got %d events, want 1: %+v", i, len(got), got)
+					continue
+				}
+				e := got[0]
+				if e.State != want.state || e.Source != want.source || e.Count != want.count ||
+					e.Suppressed != want.suppressed || len(e.Samples) != want.samples {
+					t.Errorf("step %d: got %s source=%q count=%d suppressed=%d samples=%d, want %s source=%q count=%d suppressed=%d samples=%d",
+						i, e.State, e.Source, e.Count, e.Suppressed, len(e.Samples),
+						want.state, want.source, want.count, want.suppressed, want.samples)
+				}
+				if e.Rule != rule.Name || e.Kind != rule.Kind().String() {
+					t.Errorf("step %d: got rule %q kind %q, want %q %q", i, e.Rule, e.Kind, rule.Name, rule.Kind())
+				}
+			}
+		})
+	}
+}
+
+func TestCompile(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    ruleFile
+		kind    Kind
+		wantErr bool
+	}{
+		{"instant", ruleFile{Name: "fatal", Level: "FATAL"}, Instant, false},
+		{"threshold", ruleFile{Name: "burst", Threshold: 20, Window: "1m", For: "2m"}, Threshold, false},
+		{"absence", ruleFile{Name: "silence", Absent: "5m"}, Absence, false},
+		{"missing name", ruleFile{Level: "ERROR"}, Instant, true},
+		{"threshold without window", ruleFile{Name: "x", Threshold: 3}, Instant, true},
+		{"window and absent", ruleFile{Name: "x", Window: "1m", Absent: "1m"}, Instant, true},
+		{"for on instant rule", ruleFile{Name: "x", For: "1m"}, Instant, true},
+		{"bad duration", ruleFile{Name: "x", Window: "soon"}, Instant, true},
+		{"unknown level", ruleFile{Name: "x", Level: "LOUD"}, Instant, true},
+		{"bad query", ruleFile{Name: "x", Query: "level="}, Instant, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := tt.file.compile(time.UTC)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("compile succeeded, want an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("compile: %v", err)
+			}
+			if rule.Kind() != tt.kind {
+				t.Errorf("kind = %s, want %s", rule.Kind(), tt.kind)
+			}
+			if rule.Samples != DefaultSamples {
+				t.Errorf("samples = %d, want %d", rule.Samples, DefaultSamples)
+			}
+		})
+	}
+}
+
+func TestCompileMatch(t *testing.T) {
+	rule, err := ruleFile{Name: "oom", Level: "WARN", Source: "api.log", Pattern: "OOMKilled"}.compile(time.UTC)
+	if err != nil {
+		t.Fatalf("compile: %v", err)
+	}
+
+	tests := []struct {
+		entry models.LogEntry
+		want  bool
+	}{
+		{models.LogEntry{Level: models.ERROR, Source: "api.log", Message: "pod OOMKilled"}, true},
+		{models.LogEntry{Level: models.INFO, Source: "api.log", Message: "pod OOMKilled"}, false},
+		{models.LogEntry{Level: models.ERROR, Source: "db.log", Message: "pod OOMKilled"}, false},
+		{models.LogEntry{Level: models.ERROR, Source: "api.log", Message: "pod started"}, false},
+	}
+	for _, tt := range tests {
+		if got := rule.Match(&tt.entry); got != tt.want {
+			t.Errorf("Match(%s) = %v, want %v", tt.entry, got, tt.want)
+		}
+	}
+}
+
+func TestThresholdKeepsOnlySamples(t *testing.T) {
+	start := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	rule := &Rule{Name: "burst", Match: errorsOnly, Threshold: 1000, Window: time.Minute, Samples: 3}
+	engine := NewEngine([]*Rule{rule}, start)
+	g := engine.rules[0].group("", start)
+
+	// A burst far above the samples keeps their read times, but only the
+	// first entries
+	var events []Event
+	for i := 0; i < 1500; i++ {
+		e := &models.LogEntry{Level: models.ERROR, Source: "app.log", Message: fmt.Sprintf("m%d", i)}
+		events = append(events, engine.Observe(e, start.Add(time.Duration(i)*10*time.Millisecond))...)
+	}
+	if len(g.recent) != 1500 || len(g.samples) != 3 {
+		t.Fatalf("kept %d read times and %d samples, want 1500 and 3", len(g.recent), len(g.samples))
+	}
+	if len(events) != 1 || events[0].Count != 1001 || !slices.Equal(sampleMessages(events[0]), []string{"m0", "m1", "m2"}) {
+		t.Fatalf("events = %+v, want one alert at 1001 entries with the first 3 as samples", events)
+	}
+
+	// As the first entries leave the window, their samples go too and
+	// later matches take their place
+	now := start.Add(time.Minute + 15*time.Millisecond) // m0 and m1 expired
+	engine.Evaluate(now)
+	if len(g.recent) != 1498 || len(g.samples) != 1 {
+		t.Fatalf("after expiry kept %d read times and %d samples, want 1498 and 1", len(g.recent), len(g.samples))
+	}
+	engine.Observe(&models.LogEntry{Level: models.ERROR, Message: "late"}, now)
+	if got := g.samples; len(got) != 2 || got[0].entry.Message != "m2" || got[1].entry.Message != "late" {
+		t.Errorf("samples after expiry = %v, want m2 and late", got)
+	}
+}
+
+// sampleMessages returns the messages of an event's samples
+func sampleMessages(e Event) []string {
+	var messages []string
+	for _, s := range e.Samples {
+		messages = append(messages, s.Message)
+	}
+	return messages
+}
@@ -0,0 +1,65 @@
+package alert
+
+import (
+	"time"
+
+	"github.com/aadithyaa9/loganalyzer/internal/models"
+)
+
+// State is whether an alert starts or ends (enum pattern)
+type State int
+
+const (
+	Firing State = iota
+	Resolved
+)
+
+// String implements the Stringer interface for State
+func (s State) String() string {
+	switch s {
+	case Firing:
+		return "firing"
+	case Resolved:
+		return "resolved"
+	default:
+		return "unknown"
+	}
+}
+
+// MarshalText encodes the state by name
+func (s State) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// Event is an alert raised or resolved by a rule
+type Event struct {
+	Rule       string    `json:"rule"`
+	Kind       string    `json:"kind"`
+	State      State     `json:"state"`
+	Source     string    `json:"source,omitempty"` // Set for rules evaluated per source
+	Time       time.Time `json:"time"`
+	Summary    string    `json:"summary"`
+	Count      int       `json:"count"`                // Matching entries that triggered the event
+	Suppressed int       `json:"suppressed,omitempty"` // Duplicates dropped since the previous alert
+	Samples    []Sample  `json:"samples,omitempty"`
+}
+
+// Sample is a matching line carried by an alert
+type Sample struct {
+	Timestamp time.Time `json:"timestamp"`
+	Level     string    `json:"level"`
+	Source    string    `json:"source"`
+	Location  string    `json:"location"` // path:line, for the show command
+	Message   string    `json:"message"`
+}
+
+// newSample records an entry as a sample
+func newSample(entry *models.LogEntry) Sample {
+	return Sample{
+		Timestamp: entry.Timestamp,
+		Level:     entry.Level.String(),
+		Source:    entry.Source,
+		Location:  entry.Location(),
+		Message:   entry.Message,
+	}
+}
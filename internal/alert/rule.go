@@ -0,0 +1,201 @@
+package alert
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/aadithyaa9/loganalyzer/internal/analyzer"
+	"github.com/aadithyaa9/loganalyzer/internal/matcher"
+	"github.com/aadithyaa9/loganalyzer/internal/models"
+)
+
+// DefaultSamples is how many matching lines an alert carries by default
+const DefaultSamples = 5
+
+// Kind is how a rule decides to fire (enum pattern)
+type Kind int
+
+const (
+	Instant   Kind = iota // Every matching entry
+	Threshold             // More than Threshold matching entries within Window
+	Absence               // No matching entry for Absent
+)
+
+// String implements the Stringer interface for Kind
+func (k Kind) String() string {
+	switch k {
+	case Instant:
+		return "instant"
+	case Threshold:
+		return "threshold"
+	case Absence:
+		return "absence"
+	default:
+		return "unknown"
+	}
+}
+
+// Rule is a condition on the stream of entries that raises alerts
+type Rule struct {
+	Name  string
+	Match analyzer.FilterFunc // Selects the entries the rule looks at
+
+	Threshold int
+	Window    time.Duration
+	Absent    time.Duration
+
+	For       time.Duration // How long the condition must hold before firing
+	Cooldown  time.Duration // Minimum time between alerts of one source (and message template, for instant rules)
+	PerSource bool          // Evaluate every source on its own
+	Samples   int           // Matching lines carried by an alert
+}
+
+// Kind returns how the rule fires
+func (r *Rule) Kind() Kind {
+	switch {
+	case r.Absent > 0:
+		return Absence
+	case r.Window > 0:
+		return Threshold
+	default:
+		return Instant
+	}
+}
+
+// ruleFile is the on-disk form of a rule
+type ruleFile struct {
+	Name      string `json:"name"`
+	Query     string `json:"query"`
+	Level     string `json:"level"`
+	Source    string `json:"source"`
+	Pattern   string `json:"pattern"`
+	Threshold int    `json:"threshold"`
+	Window    string `json:"window"`
+	Absent    string `json:"absent"`
+	For       string `json:"for"`
+	Cooldown  string `json:"cooldown"`
+	PerSource bool   `json:"per_source"`
+	Samples   int    `json:"samples"`
+}
+
+// LoadRules reads rules from a JSON file holding an array such as:
+//
+//	[{"name": "api-errors", "level": "ERROR", "source": "api.log",
+//	  "threshold": 20, "window": "1m", "for": "2m", "cooldown": "10m"},
+//	 {"name": "fatal", "level": "FATAL"},
+//	 {"name": "oom", "pattern": "OOMKilled", "cooldown": "5m"},
+//	 {"name": "silence", "absent": "5m"}]
+//
+// An entry matches a rule when it is at least "level", comes from
+// "source", contains "pattern" and satisfies the --query expression in
+// "query"; omitted criteria match everything. Relative times and naive
+// timestamps in queries use loc.
+func LoadRules(path string, loc *time.Location) ([]*Rule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read alert rules: %w", err)
+	}
+
+	var files []ruleFile
+	if err := json.Unmarshal(data, &files); err != nil {
+		return nil, fmt.Errorf("invalid alert rules %s: %w", path, err)
+	}
+
+	rules := make([]*Rule, 0, len(files))
+	names := make(map[string]bool, len(files))
+	for i, file := range files {
+		rule, err := file.compile(loc)
+		if err != nil {
+			return nil, fmt.Errorf("invalid alert rule %d in %s: %w", i+1, path, err)
+		}
+		if names[rule.Name] {
+			return nil, fmt.Errorf("invalid alert rules %s: duplicate rule name %q", path, rule.Name)
+		}
+		names[rule.Name] = true
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// QueryRule returns a rule firing on every entry matching a --query
+// expression, named after it
+func QueryRule(query string, loc *time.Location) (*Rule, error) {
+	return ruleFile{Name: query, Query: query}.compile(loc)
+}
+
+// compile validates a rule and builds its entry filter
+func (f ruleFile) compile(loc *time.Location) (*Rule, error) {
+	if f.Name == "" {
+		return nil, fmt.Errorf("missing name")
+	}
+	rule := &Rule{
+		Name:      f.Name,
+		Threshold: f.Threshold,
+		PerSource: f.PerSource,
+		Samples:   f.Samples,
+	}
+
+	var err error
+	durations := []struct {
+		name  string
+		value string
+		into  *time.Duration
+	}{
+		{"window", f.Window, &rule.Window},
+		{"absent", f.Absent, &rule.Absent},
+		{"for", f.For, &rule.For},
+		{"cooldown", f.Cooldown, &rule.Cooldown},
+	}
+	for _, d := range durations {
+		if d.value == "" {
+			continue
+		}
+		if *d.into, err = time.ParseDuration(d.value); err != nil || *d.into <= 0 {
+			return nil, fmt.Errorf("%s: %s %q is not a positive duration", f.Name, d.name, d.value)
+		}
+	}
+
+	switch {
+	case rule.Window > 0 && rule.Absent > 0:
+		return nil, fmt.Errorf("%s: window and absent exclude each other", f.Name)
+	case f.Threshold != 0 && rule.Window == 0:
+		return nil, fmt.Errorf("%s: threshold needs a window", f.Name)
+	case f.Threshold < 0:
+		return nil, fmt.Errorf("%s: threshold must not be negative", f.Name)
+	case rule.For > 0 && rule.Kind() == Instant:
+		return nil, fmt.Errorf("%s: for needs a window or absent", f.Name)
+	case f.Samples < 0:
+		return nil, fmt.Errorf("%s: samples must not be negative", f.Name)
+	}
+	if rule.Samples == 0 {
+		rule.Samples = DefaultSamples
+	}
+
+	// Combine the criteria into one filter
+	var filters []analyzer.FilterFunc
+	if f.Level != "" {
+		level := models.ParseLogLevel(strings.ToUpper(f.Level))
+		if level == models.UNKNOWN {
+			return nil, fmt.Errorf("%s: unknown level %q", f.Name, f.Level)
+		}
+		filters = append(filters, analyzer.MinLevelFilter(level))
+	}
+	if f.Source != "" {
+		filters = append(filters, analyzer.SourceFilter(f.Source))
+	}
+	if f.Pattern != "" {
+		filters = append(filters, analyzer.PatternFilter(matcher.NewLiteral(f.Pattern)))
+	}
+	if f.Query != "" {
+		filter, err := analyzer.ParseQuery(f.Query, loc)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", f.Name, err)
+		}
+		filters = append(filters, filter)
+	}
+	rule.Match = analyzer.CombineFilters(filters...)
+	return rule, nil
+}
@@ -1,101 +1,138 @@
 package analyzer
 
 import (
-	"sort"
 	"sync"
 
 	"github.com/aadithyaa9/loganalyzer/internal/models"
+	"github.com/aadithyaa9/loganalyzer/internal/templates"
 )
 
-// Aggregator aggregates log entries from multiple sources
+// Aggregator aggregates log entries from multiple sources. Statistics and
+// error templates are always updated; which entries are kept in memory is
+// up to the EntryStore, and extra sinks can observe the stream.
 type Aggregator struct {
-	mu      sync.Mutex
-	entries []*models.LogEntry
-	stats   *models.Statistics
+	mu    sync.Mutex
+	store EntryStore
+	sinks []Sink
+	stats *models.Statistics
+	miner *templates.Miner // Clusters ERROR/FATAL messages into templates
+
+	skipTemplates bool // Leave the miner empty
 }
 
-// NewAggregator creates a new Aggregator
+// NewAggregator creates a new Aggregator that retains every entry
 func NewAggregator() *Aggregator {
+	return NewAggregatorWithStore(NewEntryStore(RetainAll, 0))
+}
+
+// NewAggregatorWithStore creates a new Aggregator with the given entry store
+func NewAggregatorWithStore(store EntryStore) *Aggregator {
 	return &Aggregator{
-		entries: make([]*models.LogEntry, 0),
-		stats:   models.NewStatistics(),
+		store: store,
+		stats: models.NewStatistics(),
+		miner: templates.NewMiner(),
 	}
 }
 
-// Add adds a log entry to the aggregator (thread-safe)
-func (a *Aggregator) Add(entry *models.LogEntry) {
+// AddSink registers an additional sink that sees every entry
+func (a *Aggregator) AddSink(sink Sink) {
 	a.mu.Lock()
 	defer a.mu.Unlock()
+	a.sinks = append(a.sinks, sink)
+}
 
-	a.entries = append(a.entries, entry)
-	a.stats.AddEntry(entry)
+// Add adds a log entry to the aggregator (thread-safe)
+func (a *Aggregator) Add(entry *models.LogEntry) {
+	a.AddBatch([]*models.LogEntry{entry})
 }
 
-// AddBatch adds multiple entries at once (thread-safe)
+// AddBatch adds multiple entries at once (thread-safe). Context entries
+// are kept and passed to sinks but not counted in the statistics.
 func (a *Aggregator) AddBatch(entries []*models.LogEntry) {
 	a.mu.Lock()
 	defer a.mu.Unlock()
 
 	for _, entry := range entries {
-		a.entries = append(a.entries, entry)
+		if entry.Context {
+			continue
+		}
 		a.stats.AddEntry(entry)
+		a.mine(entry)
+	}
+
+	a.store.Consume(entries)
+	for _, sink := range a.sinks {
+		sink.Consume(entries)
 	}
 }
 
-// GetEntries returns all aggregated entries (creates a copy for thread-safety)
-func (a *Aggregator) GetEntries() []*models.LogEntry {
+// AddParserCounts records which parsers parsed a source's lines (thread-safe)
+func (a *Aggregator) AddParserCounts(source string, counts map[string]int) {
 	a.mu.Lock()
 	defer a.mu.Unlock()
+	a.stats.AddParserCounts(source, counts)
+}
 
-	// Create a copy to avoid race conditions
-	result := make([]*models.LogEntry, len(a.entries))
-	copy(result, a.entries)
-	return result
+// AddParseQuality records how well a source's lines parsed (thread-safe)
+func (a *Aggregator) AddParseQuality(source string, quality models.ParseQuality) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.stats.AddParseQuality(source, quality)
 }
 
-// GetStats returns the statistics
+// mine feeds error messages to the template miner
+func (a *Aggregator) mine(entry *models.LogEntry) {
+	if a.skipTemplates {
+		return
+	}
+	if entry.Level == models.ERROR || entry.Level == models.FATAL {
+		a.miner.Add(entry.Message)
+	}
+}
+
+// GetEntries returns the retained entries (creates a copy for thread-safety)
+func (a *Aggregator) GetEntries() []*models.LogEntry {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.store.Entries()
+}
+
+// GetStats returns the statistics, with mined error templates as PatternCounts
 func (a *Aggregator) GetStats() *models.Statistics {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.stats.SetPatternCounts(a.miner.Counts())
 	return a.stats
 }
 
-// Count returns the number of entries
+// Count returns the number of retained entries
 func (a *Aggregator) Count() int {
 	a.mu.Lock()
 	defer a.mu.Unlock()
-	return len(a.entries)
+	return a.store.Len()
 }
 
-// SortByTime sorts entries by timestamp
+// SortByTime sorts retained entries by timestamp
 func (a *Aggregator) SortByTime() {
 	a.mu.Lock()
 	defer a.mu.Unlock()
-
-	sort.Slice(a.entries, func(i, j int) bool {
-		return a.entries[i].Timestamp.Before(a.entries[j].Timestamp)
-	})
+	a.store.SortByTime()
 }
 
-// GetTopN returns top N entries (after optional filtering)
+// GetTopN returns top N retained entries
 func (a *Aggregator) GetTopN(n int) []*models.LogEntry {
-	a.mu.Lock()
-	defer a.mu.Unlock()
-
-	if n > len(a.entries) {
-		n = len(a.entries)
+	entries := a.GetEntries()
+	if n > len(entries) {
+		n = len(entries)
 	}
-
-	result := make([]*models.LogEntry, n)
-	copy(result, a.entries[:n])
-	return result
+	return entries[:n]
 }
 
-// FilterEntries returns entries that match the filter
+// FilterEntries returns retained entries that match the filter
 func (a *Aggregator) FilterEntries(filter FilterFunc) []*models.LogEntry {
-	a.mu.Lock()
-	defer a.mu.Unlock()
-
 	result := make([]*models.LogEntry, 0)
-	for _, entry := range a.entries {
+	for _, entry := range a.GetEntries() {
 		if filter(entry) {
 			result = append(result, entry)
 		}
@@ -108,6 +145,7 @@ func (a *Aggregator) Clear() {
 	a.mu.Lock()
 	defer a.mu.Unlock()
 
-	a.entries = make([]*models.LogEntry, 0)
+	a.store.Reset()
 	a.stats = models.NewStatistics()
+	a.miner = templates.NewMiner()
 }
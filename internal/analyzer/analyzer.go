@@ -1,26 +1,77 @@
 package analyzer
 
 import (
-	"bufio"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"regexp"
 	"sync"
 	"time"
 
+	"github.com/aadithyaa9/loganalyzer/internal/checkpoint"
+	"github.com/aadithyaa9/loganalyzer/internal/input"
+	"github.com/aadithyaa9/loganalyzer/internal/matcher"
 	"github.com/aadithyaa9/loganalyzer/internal/models"
 	"github.com/aadithyaa9/loganalyzer/internal/parser"
 )
 
 // Config holds analyzer configuration
 type Config struct {
-	Workers    int
-	Level      models.LogLevel
-	Pattern    string
-	StartTime  time.Time
-	EndTime    time.Time
-	AutoDetect bool
-	ParserType parser.ParserType
+	Workers   int
+	Level     models.LogLevel
+	Pattern   string
+	Matcher   matcher.Matcher // Compiled Pattern; built from Pattern as a literal if nil
+	StartTime time.Time
+	EndTime   time.Time
+	Filter    FilterFunc       // Extra predicate applied after the built-in filters
+	Parser    parser.LogParser // Forces this parser (see parser.New); nil detects it per file
+
+	// ParserOptions configure the registered parsers used for detection;
+	// each file locks onto the best of them after sampling SampleLines
+	ParserOptions parser.Options
+	SampleLines   int
+
+	// Multiline folds stack traces and other continuation lines into the
+	// preceding entry; MultilineStart overrides the built-in heuristics
+	Multiline      bool
+	MultilineStart *regexp.Regexp
+
+	// Retention decides which entries are kept for reporting; RetainLimit
+	// bounds RetainFirstN. Sinks additionally observe every entry.
+	Retention   Retention
+	RetainLimit int
+	Sinks       []Sink
+
+	// SkipTemplates leaves error templates unmined, when no report shows them
+	SkipTemplates bool
+
+	// Before and After report that many entries preceding and following
+	// each match as context, like grep -B and -A. Context follows reading
+	// order, so files are then read one at a time, unsorted and unchunked.
+	Before int
+	After  int
+
+	// Checkpoints make analysis incremental: each file is read from where
+	// the previous run stopped, and checkpointed when done. A line still
+	// being written at the end of a live file is left for the next run.
+	Checkpoints *checkpoint.Store
+
+	// DeadLetter receives records no parser accepted, one JSON object per
+	// line, instead of dropping them silently. Records the file's parser
+	// rejected and only plain text accepted are written there too.
+	DeadLetter io.Writer
+}
+
+// deadLetterRecord is one line of the dead-letter file
+type deadLetterRecord struct {
+	Source     string `json:"source"`
+	LineNumber int    `json:"line_number"`
+	Offset     int64  `json:"offset"`
+	Error      string `json:"error"`
+	Line       string `json:"line"`
 }
 
 // Analyzer processes log files concurrently
@@ -28,101 +79,222 @@ type Analyzer struct {
 	config     *Config
 	aggregator *Aggregator
 	parser     parser.LogParser
+	detector   *parser.Detector
+
+	deadLetterMu  sync.Mutex
+	deadLetterEnc *json.Encoder
+
+	sources map[string]string // Display names by path, set before workers start
 }
 
 // NewAnalyzer creates a new Analyzer
 func NewAnalyzer(config *Config) *Analyzer {
-	// Get parser based on config
-	var p parser.LogParser
-	if config.AutoDetect {
-		p = nil // Will auto-detect per line
-	} else {
-		p = parser.GetParser(config.ParserType)
+	// Compile the pattern once per run rather than per line
+	if config.Matcher == nil && config.Pattern != "" {
+		config.Matcher = matcher.NewLiteral(config.Pattern)
+	}
+
+	aggregator := NewAggregatorWithStore(NewEntryStore(config.Retention, config.RetainLimit))
+	for _, sink := range config.Sinks {
+		aggregator.AddSink(sink)
 	}
+	aggregator.skipTemplates = config.SkipTemplates
 
-	return &Analyzer{
+	a := &Analyzer{
 		config:     config,
-		aggregator: NewAggregator(),
-		parser:     p,
+		aggregator: aggregator,
+		parser:     config.Parser,
+		detector:   parser.NewDetector(config.ParserOptions),
 	}
+	if config.DeadLetter != nil {
+		a.deadLetterEnc = json.NewEncoder(config.DeadLetter)
+	}
+	return a
 }
 
-// AnalyzeFile analyzes a single log file
+// AnalyzeFile analyzes a single log file, decompressing it if needed.
+// Large uncompressed files are split into chunks parsed across the workers.
 func (a *Analyzer) AnalyzeFile(filePath string) error {
-	file, err := os.Open(filePath)
+	if files := a.withRenamed([]string{filePath}); len(files) > 1 {
+		return a.AnalyzeFiles(files)
+	}
+
+	startTime := time.Now()
+	a.sources = input.SourceNames([]string{filePath})
+	err := a.analyzeFile(filePath, true)
+	a.aggregator.GetStats().SetProcessingTime(time.Since(startTime))
+	return err
+}
+
+// analyzeFile analyzes one file, optionally in parallel chunks, from its
+// checkpoint on if there is one
+func (a *Analyzer) analyzeFile(filePath string, allowChunks bool) error {
+	start := models.Position{Line: 1}
+	if a.config.Checkpoints != nil {
+		if c, ok := a.config.Checkpoints.Lookup(filePath); ok {
+			start = c.Position()
+		}
+	}
+
+	file, err := input.Open(filePath)
 	if err != nil {
 		return fmt.Errorf("failed to open file %s: %w", filePath, err)
 	}
 	defer file.Close()
 
-	// Get file info for stats
-	fileInfo, err := file.Stat()
+	var end models.Position
+	chunks := 1
+	if allowChunks && start.Offset == 0 && file.Compression == input.None {
+		chunks = a.chunkCount(file.Size)
+	}
+	if chunks > 1 {
+		// Chunk boundaries would split the partial records and wrapped
+		// traces of container logs, so those are read in one piece
+		if wrapped, err := containerWrapped(filePath); err != nil || wrapped {
+			chunks = 1
+		}
+	}
+	if chunks > 1 {
+		file.Close()
+		end, err = a.analyzeChunked(filePath, file.Size, chunks)
+	} else if err = file.Skip(start.Offset); err == nil {
+		end, err = a.analyzeStream(file, filePath, start, a.aggregator.AddBatch, func(record parser.Record, err error) {
+			a.deadLetter(filePath, record, err)
+		})
+	}
 	if err != nil {
-		return fmt.Errorf("failed to get file info: %w", err)
+		return err
+	}
+
+	// Update stats with the content bytes read in this run, decompressed
+	a.aggregator.GetStats().AddFile(max(end.Offset-start.Offset, 0))
+
+	if a.config.Checkpoints != nil {
+		c, err := checkpoint.TakePath(filePath, end)
+		if err != nil {
+			return fmt.Errorf("failed to checkpoint %s: %w", filePath, err)
+		}
+		a.config.Checkpoints.Set(filePath, c)
 	}
+	return nil
+}
 
-	scanner := bufio.NewScanner(file)
-	// Increase buffer size for large log lines
-	buf := make([]byte, 0, 64*1024)
-	scanner.Buffer(buf, 1024*1024)
+// analyzeStream scans, assembles, parses and filters entries from r, the
+// content of filePath from position start on, handing them to emit in
+// batches and rejected records to reject. It returns the position after the
+// last line read.
+func (a *Analyzer) analyzeStream(r io.Reader, filePath string, start models.Position, emit func([]*models.LogEntry), reject func(parser.Record, error)) (models.Position, error) {
+	source := a.sourceName(filePath)
+	scanner := input.NewLineScanner(r, start)
 
-	lineCount := 0
 	entries := make([]*models.LogEntry, 0, 1000)
 
-	for scanner.Scan() {
-		lineCount++
-		line := scanner.Text()
+	// Each stream picks its own parser, so files of different formats
+	// analyzed together do not influence each other
+	selector := a.newSelector()
+	var quality models.ParseQuality
+	defer func() {
+		a.aggregator.AddParserCounts(source, selector.Counts())
+		a.aggregator.AddParseQuality(source, quality)
+	}()
+
+	// Files under the kubelet and docker log directories name their pod
+	containerFields := parser.ContainerPathFields(filePath)
+
+	var window *models.ContextWindow
+	if a.withContext() {
+		window = models.NewContextWindow(a.config.Before, a.config.After)
+	}
 
-		if line == "" {
-			continue
-		}
+	// batch queues an entry, handing full batches to emit
+	batch := func(entry *models.LogEntry) {
+		entries = append(entries, entry)
 
-		// Auto-detect parser if needed
-		currentParser := a.parser
-		if currentParser == nil {
-			currentParser = parser.DetectParser(line)
+		// Batch insert to reduce lock contention
+		if len(entries) >= 1000 {
+			emit(entries)
+			entries = make([]*models.LogEntry, 0, 1000)
 		}
+	}
 
-		// Parse the line
-		entry, err := currentParser.Parse(line, filepath.Base(filePath))
+	// processRecord parses one assembled entry and batches it
+	processRecord := func(record parser.Record) {
+		// Parse the record
+		entry, err := selector.Parse(record.Text, source)
+		if errors.Is(err, parser.ErrEmptyLine) {
+			return
+		}
+		quality.Records++
 		if err != nil {
-			// Skip invalid lines
-			continue
+			quality.Failed++
+			reject(record, err)
+			return
+		}
+		entry.Path = filePath
+		record.Apply(entry)
+		if mismatch := selector.Mismatch(); mismatch != nil {
+			// Kept as plain text, but reported like a failure
+			quality.Mismatched++
+			reject(record, mismatch)
+		} else {
+			quality.Observe(entry)
+		}
+		for key, value := range containerFields {
+			if entry.Fields == nil {
+				entry.Fields = make(models.Fields, len(containerFields))
+			}
+			entry.Fields[key] = value
 		}
 
-		// Apply filters
-		if !a.shouldInclude(entry) {
-			continue
+		// Apply filters, keeping the entries around matches as context
+		match := a.shouldInclude(entry)
+		if window == nil {
+			if match {
+				batch(entry)
+			}
+			return
+		}
+		for _, e := range window.Add(entry, match) {
+			batch(e)
 		}
+	}
 
-		entries = append(entries, entry)
+	// Incremental runs leave a line still being written for the next run
+	holdPartial := a.config.Checkpoints != nil && input.IsLiveLogFile(filePath)
 
-		// Batch insert to reduce lock contention
-		if len(entries) >= 1000 {
-			a.aggregator.AddBatch(entries)
-			entries = make([]*models.LogEntry, 0, 1000)
+	assembler := parser.NewRecordAssembler(a.newAssembler())
+	end := start
+	for scanner.Scan() {
+		if holdPartial && scanner.Partial() {
+			break
+		}
+		end = scanner.Next()
+		if record, ok := assembler.Add(scanner.Text(), scanner.Position()); ok {
+			processRecord(record)
+		}
+	}
+	for {
+		record, ok := assembler.Flush()
+		if !ok {
+			break
 		}
+		processRecord(record)
 	}
 
 	// Add remaining entries
 	if len(entries) > 0 {
-		a.aggregator.AddBatch(entries)
+		emit(entries)
 	}
 
 	if err := scanner.Err(); err != nil {
-		return fmt.Errorf("error reading file: %w", err)
+		return end, fmt.Errorf("error reading file: %w", err)
 	}
 
-	// Update stats
-	a.aggregator.GetStats().AddFile(fileInfo.Size())
-
-	return nil
+	return end, nil
 }
 
 // AnalyzeDirectory analyzes all log files in a directory concurrently
 func (a *Analyzer) AnalyzeDirectory(dirPath string) error {
-	startTime := time.Now()
-
 	// Find all log files
 	files, err := findLogFiles(dirPath)
 	if err != nil {
@@ -133,11 +305,17 @@ func (a *Analyzer) AnalyzeDirectory(dirPath string) error {
 		return fmt.Errorf("no log files found in %s", dirPath)
 	}
 
+	return a.AnalyzeFiles(files)
+}
+
+// AnalyzeFiles analyzes the given log files concurrently
+func (a *Analyzer) AnalyzeFiles(files []string) error {
+	startTime := time.Now()
+	files = a.withRenamed(files)
+	a.sources = input.SourceNames(files)
+
 	// Create worker pool
-	numWorkers := a.config.Workers
-	if numWorkers <= 0 {
-		numWorkers = 4 // Default
-	}
+	numWorkers := a.workerCount()
 
 	// Channels for work distribution
 	fileChan := make(chan string, len(files))
@@ -156,7 +334,8 @@ func (a *Analyzer) AnalyzeDirectory(dirPath string) error {
 		go func(workerID int) {
 			defer wg.Done()
 			for file := range fileChan {
-				if err := a.AnalyzeFile(file); err != nil {
+				// A lone file is split into chunks instead of occupying one worker
+				if err := a.analyzeFile(file, len(files) == 1); err != nil {
 					errChan <- fmt.Errorf("worker %d: %w", workerID, err)
 				}
 			}
@@ -176,8 +355,10 @@ func (a *Analyzer) AnalyzeDirectory(dirPath string) error {
 	// Set processing time
 	a.aggregator.GetStats().SetProcessingTime(time.Since(startTime))
 
-	// Sort entries by time
-	a.aggregator.SortByTime()
+	// Sort entries by time; context stays in reading order
+	if !a.withContext() {
+		a.aggregator.SortByTime()
+	}
 
 	if len(errors) > 0 {
 		return fmt.Errorf("encountered %d errors during processing", len(errors))
@@ -186,6 +367,75 @@ func (a *Analyzer) AnalyzeDirectory(dirPath string) error {
 	return nil
 }
 
+// withRenamed adds to files the rotated files that still hold unread lines:
+// when a checkpointed file was rotated since the last run, its remainder
+// is read from the renamed file ahead of the new one
+func (a *Analyzer) withRenamed(files []string) []string {
+	if a.config.Checkpoints == nil {
+		return files
+	}
+
+	listed := make(map[string]bool, len(files))
+	for _, file := range files {
+		listed[filepath.Clean(file)] = true
+	}
+	result := make([]string, 0, len(files))
+	for _, file := range files {
+		if old, ok := a.config.Checkpoints.Renamed(file); ok && !listed[filepath.Clean(old)] {
+			listed[filepath.Clean(old)] = true
+			result = append(result, old)
+		}
+		result = append(result, file)
+	}
+	return result
+}
+
+// deadLetter writes a rejected record to the dead-letter writer, if any
+func (a *Analyzer) deadLetter(filePath string, record parser.Record, parseErr error) {
+	if a.deadLetterEnc == nil {
+		return
+	}
+	a.deadLetterMu.Lock()
+	defer a.deadLetterMu.Unlock()
+	// A failing dead-letter file must not abort the analysis
+	_ = a.deadLetterEnc.Encode(deadLetterRecord{
+		Source:     filePath,
+		LineNumber: record.Position.Line,
+		Offset:     record.Position.Offset,
+		Error:      parseErr.Error(),
+		Line:       record.Text,
+	})
+}
+
+// withContext reports whether context entries are kept around matches
+func (a *Analyzer) withContext() bool {
+	return a.config.Before > 0 || a.config.After > 0
+}
+
+// sourceName returns the display name of a file
+func (a *Analyzer) sourceName(filePath string) string {
+	if name, ok := a.sources[filePath]; ok {
+		return name
+	}
+	return filepath.Base(filePath)
+}
+
+// newSelector returns the parser selector for one stream
+func (a *Analyzer) newSelector() *parser.Selector {
+	if a.parser != nil {
+		return parser.NewFixedSelector(a.parser)
+	}
+	return a.detector.NewSelector(a.config.SampleLines)
+}
+
+// newAssembler returns a multiline assembler, or nil if multiline assembly is off
+func (a *Analyzer) newAssembler() *parser.MultilineAssembler {
+	if !a.config.Multiline {
+		return nil
+	}
+	return parser.NewMultilineAssembler(a.config.MultilineStart)
+}
+
 // GetResults returns the aggregated results
 func (a *Analyzer) GetResults() *Aggregator {
 	return a.aggregator
@@ -194,12 +444,12 @@ func (a *Analyzer) GetResults() *Aggregator {
 // shouldInclude checks if an entry should be included based on filters
 func (a *Analyzer) shouldInclude(entry *models.LogEntry) bool {
 	// Level filter
-	if a.config.Level != models.UNKNOWN && entry.Level < a.config.Level {
+	if a.config.Level != models.UNKNOWN && !entry.Level.AtLeast(a.config.Level) {
 		return false
 	}
 
 	// Pattern filter
-	if a.config.Pattern != "" && !entry.MatchesPattern(a.config.Pattern) {
+	if a.config.Matcher != nil && !matcher.MatchEntry(a.config.Matcher, entry) {
 		return false
 	}
 
@@ -211,10 +461,16 @@ func (a *Analyzer) shouldInclude(entry *models.LogEntry) bool {
 		return false
 	}
 
+	// Custom filter (field filters, queries)
+	if a.config.Filter != nil && !a.config.Filter(entry) {
+		return false
+	}
+
 	return true
 }
 
-// findLogFiles recursively finds all .log files in a directory
+// findLogFiles recursively finds all live and rotated log files in a directory,
+// with each rotation set ordered oldest first
 func findLogFiles(dirPath string) ([]string, error) {
 	var files []string
 
@@ -223,12 +479,13 @@ func findLogFiles(dirPath string) ([]string, error) {
 			return err
 		}
 
-		if !info.IsDir() && filepath.Ext(path) == ".log" {
+		if !info.IsDir() && input.IsLogFile(path) {
 			files = append(files, path)
 		}
 
 		return nil
 	})
 
+	input.SortChronologically(files)
 	return files, err
 }
@@ -0,0 +1,72 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aadithyaa9/loganalyzer/internal/models"
+)
+
+func TestPlainLinesWithoutLevel(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	content := "2024-01-15 10:00:00 server started\n" +
+		"2024-01-15 10:00:01 ERROR disk full\n" +
+		"2024-01-15 10:00:02 listening on :8080\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		level   models.LogLevel
+		entries int
+	}{
+		{models.UNKNOWN, 3}, // No --level
+		{models.DEBUG, 3},
+		{models.INFO, 3},
+		{models.ERROR, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.level.String(), func(t *testing.T) {
+			a := NewAnalyzer(&Config{Workers: 1, Level: tt.level})
+			if err := a.AnalyzeFile(path); err != nil {
+				t.Fatal(err)
+			}
+			stats := a.GetResults().GetStats()
+			if stats.TotalEntries != tt.entries {
+				t.Errorf("TotalEntries = %d, want %d", stats.TotalEntries, tt.entries)
+			}
+
+			// Lines without a level are INFO, which is not a parse problem
+			quality := stats.TotalParseQuality()
+			if quality.LevelDefault != 2 || quality.UnknownLevel != 0 || !quality.Clean() || quality.BadRatio() != 0 {
+				t.Errorf("quality = %+v, want 2 default levels and nothing bad", quality)
+			}
+		})
+	}
+}
+
+func TestSkipTemplates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	content := "2024-01-15 10:00:00 ERROR connection to 10.0.0.1 refused\n" +
+		"2024-01-15 10:00:01 ERROR connection to 10.0.0.2 refused\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, skip := range []bool{false, true} {
+		a := NewAnalyzer(&Config{Workers: 1, Retention: RetainNone, SkipTemplates: skip})
+		if err := a.AnalyzeFile(path); err != nil {
+			t.Fatal(err)
+		}
+		stats := a.GetResults().GetStats()
+		want := 1
+		if skip {
+			want = 0
+		}
+		if len(stats.PatternCounts) != want || stats.TotalEntries != 2 {
+			t.Errorf("SkipTemplates %v: %d templates of %d entries, want %d of 2", skip, len(stats.PatternCounts), stats.TotalEntries, want)
+		}
+	}
+}
@@ -0,0 +1,270 @@
+package analyzer
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/aadithyaa9/loganalyzer/internal/models"
+	"github.com/aadithyaa9/loganalyzer/internal/parser"
+)
+
+const (
+	// minChunkSize is the smallest byte range worth handing to a worker
+	minChunkSize = 4 * 1024 * 1024
+
+	// chunksPerWorker oversplits the file so uneven chunks balance out
+	chunksPerWorker = 4
+
+	// maxAlignScan bounds the search for an entry start after a chunk boundary
+	maxAlignScan = 1024 * 1024
+)
+
+// fileChunk is a byte range of a file aligned to entry boundaries
+type fileChunk struct {
+	index      int
+	start, end int64
+}
+
+// rejectedRecord is a record of a chunk headed for the dead-letter file
+type rejectedRecord struct {
+	record parser.Record
+	err    error
+}
+
+// chunkResult carries a batch (or completion) of one chunk to the merger.
+// Line numbers count from the chunk's first line; the merger shifts them
+// by the lines of the chunks before.
+type chunkResult struct {
+	index   int
+	batch   []*models.LogEntry
+	rejects []rejectedRecord
+	done    bool
+	end     models.Position // Where the chunk's last line ends, once done
+	err     error
+}
+
+// workerCount returns the configured number of workers
+func (a *Analyzer) workerCount() int {
+	if a.withContext() {
+		return 1 // Context follows reading order
+	}
+	if a.config.Workers <= 0 {
+		return 4 // Default
+	}
+	return a.config.Workers
+}
+
+// chunkCount decides how many chunks a file of the given size is split into
+func (a *Analyzer) chunkCount(size int64) int {
+	workers := a.workerCount()
+	if workers <= 1 {
+		return 1
+	}
+
+	chunks := int(size / minChunkSize)
+	if chunks > workers*chunksPerWorker {
+		chunks = workers * chunksPerWorker
+	}
+	return chunks
+}
+
+// analyzeChunked parses byte ranges of a file in parallel and merges the
+// results into the aggregator in file order. It returns the position after
+// the last line read.
+func (a *Analyzer) analyzeChunked(filePath string, size int64, n int) (models.Position, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return models.Position{}, fmt.Errorf("failed to open file %s: %w", filePath, err)
+	}
+	defer file.Close()
+
+	chunks, err := a.splitFile(file, size, n)
+	if err != nil {
+		return models.Position{}, err
+	}
+
+	numWorkers := a.workerCount()
+
+	// Only numWorkers chunks are in flight at once, which bounds how many
+	// out-of-order batches the merger has to hold back
+	chunkChan := make(chan fileChunk, len(chunks))
+	results := make(chan chunkResult, numWorkers*2)
+	queued := 0
+	for ; queued < len(chunks) && queued < numWorkers; queued++ {
+		chunkChan <- chunks[queued]
+	}
+	if queued == len(chunks) {
+		close(chunkChan)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < numWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for c := range chunkChan {
+				section := io.NewSectionReader(file, c.start, c.end-c.start)
+				start := models.Position{Line: 1, Offset: c.start}
+				var rejects []rejectedRecord
+				end, err := a.analyzeStream(section, filePath, start, func(batch []*models.LogEntry) {
+					results <- chunkResult{index: c.index, batch: batch, rejects: rejects}
+					rejects = nil
+				}, func(record parser.Record, err error) {
+					rejects = append(rejects, rejectedRecord{record, err})
+				})
+				results <- chunkResult{index: c.index, rejects: rejects, done: true, end: end, err: err}
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	// Merge batches in chunk order
+	var firstErr error
+	var end models.Position
+	pending := make(map[int][]chunkResult)
+	next := 0
+	base := 0 // Lines in the chunks before next
+
+	apply := func(r chunkResult) {
+		for _, entry := range r.batch {
+			entry.Line += base
+		}
+		for _, rejected := range r.rejects {
+			rejected.record.Position.Line += base
+			a.deadLetter(filePath, rejected.record, rejected.err)
+		}
+		if !r.done {
+			a.aggregator.AddBatch(r.batch)
+			return
+		}
+		if r.err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("chunk %d of %s: %w", r.index, filePath, r.err)
+		}
+		end = r.end
+		end.Line += base
+		base = end.Line - 1
+		next++
+		if queued < len(chunks) {
+			chunkChan <- chunks[queued]
+			queued++
+			if queued == len(chunks) {
+				close(chunkChan)
+			}
+		}
+	}
+
+	for r := range results {
+		if r.index != next {
+			pending[r.index] = append(pending[r.index], r)
+			continue
+		}
+		apply(r)
+
+		// Release batches of chunks that are now up next
+		for len(pending[next]) > 0 {
+			current := next
+			queue := pending[current]
+			delete(pending, current)
+			for _, held := range queue {
+				apply(held)
+			}
+		}
+	}
+
+	return end, firstErr
+}
+
+// splitFile computes n chunks whose boundaries fall on entry starts
+func (a *Analyzer) splitFile(file *os.File, size int64, n int) ([]fileChunk, error) {
+	boundaries := []int64{0}
+	for i := 1; i < n; i++ {
+		offset, err := a.alignToEntry(file, size*int64(i)/int64(n), size)
+		if err != nil {
+			return nil, fmt.Errorf("failed to split file: %w", err)
+		}
+		if offset > boundaries[len(boundaries)-1] && offset < size {
+			boundaries = append(boundaries, offset)
+		}
+	}
+	boundaries = append(boundaries, size)
+
+	chunks := make([]fileChunk, 0, len(boundaries)-1)
+	for i := 0; i+1 < len(boundaries); i++ {
+		chunks = append(chunks, fileChunk{index: i, start: boundaries[i], end: boundaries[i+1]})
+	}
+	return chunks, nil
+}
+
+// containerWrapped reports whether the first line of a file carries a
+// container runtime envelope
+func containerWrapped(filePath string) (bool, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return false, err
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(io.LimitReader(file, maxAlignScan))
+	for {
+		line, err := reader.ReadString('\n')
+		if strings.TrimSpace(line) != "" {
+			return parser.DetectContainerFormat(strings.TrimRight(line, "\r\n")) != parser.ContainerNone, nil
+		}
+		if err == io.EOF {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+	}
+}
+
+// alignToEntry moves offset forward to the start of the next line, and
+// with multiline assembly on, past continuation lines to the next entry
+func (a *Analyzer) alignToEntry(file *os.File, offset, size int64) (int64, error) {
+	if offset <= 0 {
+		return 0, nil
+	}
+
+	// Start one byte early so an offset already on a line start is kept
+	reader := bufio.NewReader(io.NewSectionReader(file, offset-1, size-offset+1))
+	skipped, err := reader.ReadString('\n')
+	if err == io.EOF {
+		return size, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	lineStart := offset - 1 + int64(len(skipped))
+
+	assembler := a.newAssembler()
+	if assembler == nil {
+		return lineStart, nil
+	}
+
+	pos := lineStart
+	for pos-lineStart < maxAlignScan {
+		line, err := reader.ReadString('\n')
+		if len(line) > 0 && assembler.IsEntryStart(strings.TrimRight(line, "\r\n")) {
+			return pos, nil
+		}
+		pos += int64(len(line))
+		if err == io.EOF {
+			return size, nil
+		}
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	// No recognizable entry start nearby; fall back to the line boundary
+	return lineStart, nil
+}
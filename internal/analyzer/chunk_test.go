@@ -0,0 +1,153 @@
+package analyzer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aadithyaa9/loganalyzer/internal/models"
+)
+
+// writeLog generates a plain text log with stack traces spread through it
+func writeLog(t *testing.T, entries int) string {
+	t.Helper()
+	levels := []string{"INFO", "DEBUG", "WARN", "ERROR"}
+	start := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	var b strings.Builder
+	for i := 0; i < entries; i++ {
+		ts := start.Add(time.Duration(i) * time.Second).Format("2006-01-02 15:04:05")
+		fmt.Fprintf(&b, "%s %s request %d handled in %dms\n", ts, levels[i%len(levels)], i, i%97)
+		if i%13 == 0 {
+			fmt.Fprintf(&b, "java.lang.IllegalStateException: request %d\n", i)
+			for frame := 0; frame < i%5+1; frame++ {
+				fmt.Fprintf(&b, "\tat com.example.Handler.step%d(Handler.java:%d)\n", frame, 10+frame)
+			}
+			b.WriteString("Caused by: java.io.IOException: broken pipe\n\t... 3 more\n")
+		}
+	}
+
+	path := filepath.Join(t.TempDir(), "app.log")
+	if err := os.WriteFile(path, []byte(b.String()), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestAnalyzeChunkedMatchesSequential(t *testing.T) {
+	path := writeLog(t, 3000)
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newAnalyzer := func(workers int) *Analyzer {
+		return NewAnalyzer(&Config{Workers: workers, Multiline: true})
+	}
+
+	sequential := newAnalyzer(1)
+	if err := sequential.AnalyzeFile(path); err != nil {
+		t.Fatalf("sequential: %v", err)
+	}
+	want := sequential.GetResults().GetEntries()
+
+	for _, chunks := range []int{2, 7, 31} {
+		t.Run(fmt.Sprintf("%d chunks", chunks), func(t *testing.T) {
+			chunked := newAnalyzer(4)
+			end, err := chunked.analyzeChunked(path, info.Size(), chunks)
+			if err != nil {
+				t.Fatalf("chunked: %v", err)
+			}
+			if end.Offset != info.Size() {
+				t.Errorf("end offset = %d, want %d", end.Offset, info.Size())
+			}
+
+			got := chunked.GetResults().GetEntries()
+			if len(got) != len(want) {
+				t.Fatalf("got %d entries, want %d", len(got), len(want))
+			}
+			for i := range got {
+				if got[i].Raw != want[i].Raw || got[i].Line != want[i].Line || got[i].Level != want[i].Level {
+					t.Fatalf("entry %d = line %d %q, want line %d %q", i, got[i].Line, got[i].Raw, want[i].Line, want[i].Raw)
+				}
+			}
+
+			gotStats, wantStats := chunked.GetResults().GetStats(), sequential.GetResults().GetStats()
+			for _, level := range []models.LogLevel{models.DEBUG, models.INFO, models.WARN, models.ERROR, models.UNKNOWN} {
+				if gotStats.GetLevelCount(level) != wantStats.GetLevelCount(level) {
+					t.Errorf("%s count = %d, want %d", level, gotStats.GetLevelCount(level), wantStats.GetLevelCount(level))
+				}
+			}
+		})
+	}
+}
+
+func TestSplitFileAlignsToEntries(t *testing.T) {
+	path := writeLog(t, 500)
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	info, _ := file.Stat()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a := NewAnalyzer(&Config{Multiline: true})
+	chunks, err := a.splitFile(file, info.Size(), 40)
+	if err != nil {
+		t.Fatalf("splitFile: %v", err)
+	}
+	if chunks[0].start != 0 || chunks[len(chunks)-1].end != info.Size() {
+		t.Errorf("chunks cover [%d, %d), want [0, %d)", chunks[0].start, chunks[len(chunks)-1].end, info.Size())
+	}
+	for i, c := range chunks {
+		if i > 0 && c.start != chunks[i-1].end {
+			t.Errorf("chunk %d starts at %d, previous ends at %d", i, c.start, chunks[i-1].end)
+		}
+		if c.start == 0 {
+			continue
+		}
+		// Every chunk after the first starts on an entry's first line
+		if data[c.start-1] != '\n' || !strings.HasPrefix(string(data[c.start:]), "2024-") {
+			end := min(c.start+40, int64(len(data)))
+			t.Errorf("chunk %d starts mid-entry: %q", i, data[c.start:end])
+		}
+	}
+}
+
+func TestContainerWrapped(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    bool
+	}{
+		{"CRI", "\n2024-01-15T10:00:00.000000001Z stdout P partial \n2024-01-15T10:00:00.000000002Z stdout F line\n", true},
+		{"docker", `{"log":"hello\n","stream":"stdout","time":"2024-01-15T10:00:00Z"}` + "\n", true},
+		{"plain", "2024-01-15 10:00:00 INFO hello\n", false},
+		{"json", `{"level":"info","msg":"hello"}` + "\n", false},
+		{"empty", "", false},
+	}
+
+	dir := t.TempDir()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, tt.name+".log")
+			if err := os.WriteFile(path, []byte(tt.content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			got, err := containerWrapped(path)
+			if err != nil {
+				t.Fatalf("containerWrapped: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("containerWrapped = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
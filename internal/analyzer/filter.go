@@ -3,6 +3,7 @@ package analyzer
 import (
 	"time"
 
+	"github.com/aadithyaa9/loganalyzer/internal/matcher"
 	"github.com/aadithyaa9/loganalyzer/internal/models"
 )
 
@@ -34,14 +35,14 @@ func LevelFilter(level models.LogLevel) FilterFunc {
 // MinLevelFilter creates a filter for minimum log level
 func MinLevelFilter(minLevel models.LogLevel) FilterFunc {
 	return func(entry *models.LogEntry) bool {
-		return entry.Level >= minLevel
+		return entry.Level.AtLeast(minLevel)
 	}
 }
 
-// PatternFilter creates a filter for pattern matching
-func PatternFilter(pattern string) FilterFunc {
+// PatternFilter creates a filter from a compiled matcher
+func PatternFilter(m matcher.Matcher) FilterFunc {
 	return func(entry *models.LogEntry) bool {
-		return entry.MatchesPattern(pattern)
+		return matcher.MatchEntry(m, entry)
 	}
 }
 
@@ -65,6 +66,22 @@ func SourceFilter(source string) FilterFunc {
 	}
 }
 
+// FieldFilter creates a filter for entries whose field equals value
+func FieldFilter(key, value string) FilterFunc {
+	return func(entry *models.LogEntry) bool {
+		actual, ok := entry.Fields.GetString(key)
+		return ok && actual == value
+	}
+}
+
+// FieldExistsFilter creates a filter for entries that carry a field
+func FieldExistsFilter(key string) FilterFunc {
+	return func(entry *models.LogEntry) bool {
+		_, ok := entry.Fields.Get(key)
+		return ok
+	}
+}
+
 // CombineFilters combines multiple filters with AND logic
 func CombineFilters(filters ...FilterFunc) FilterFunc {
 	return func(entry *models.LogEntry) bool {
@@ -0,0 +1,210 @@
+package analyzer
+
+import (
+	"compress/gzip"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/aadithyaa9/loganalyzer/internal/checkpoint"
+)
+
+// logLines returns n log lines tagged so files of different generations
+// differ from their first byte on
+func logLines(tag string, n int) string {
+	var b strings.Builder
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&b, "2024-01-15 10:00:%02d INFO %s line %d\n", i%60, tag, i)
+	}
+	return b.String()
+}
+
+func appendLog(t *testing.T, path, content string) {
+	t.Helper()
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	if _, err := file.WriteString(content); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// rotateLog renames path to path.1, optionally compressing it, and starts
+// a new file at path
+func rotateLog(t *testing.T, path string, compress bool, content string) {
+	t.Helper()
+	if err := os.Rename(path, path+".1"); err != nil {
+		t.Fatal(err)
+	}
+	if compress {
+		data, err := os.ReadFile(path + ".1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		file, err := os.Create(path + ".1.gz")
+		if err != nil {
+			t.Fatal(err)
+		}
+		w := gzip.NewWriter(file)
+		if _, err := w.Write(data); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+		if err := file.Close(); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Remove(path + ".1"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	appendLog(t, path, content)
+}
+
+// analyzeIncremental runs one --incremental analysis of path and returns
+// the messages read, sorted (entries sharing a timestamp come in any order)
+func analyzeIncremental(t *testing.T, statePath, path string) []string {
+	t.Helper()
+	store, err := checkpoint.Load(statePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := NewAnalyzer(&Config{Workers: 1, Multiline: true, Checkpoints: store})
+	if err := a.AnalyzeFile(path); err != nil {
+		t.Fatalf("AnalyzeFile: %v", err)
+	}
+	if err := store.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	var messages []string
+	for _, entry := range a.GetResults().GetEntries() {
+		messages = append(messages, entry.Message)
+	}
+	if got := a.GetResults().GetStats().TotalEntries; got != len(messages) {
+		t.Errorf("TotalEntries = %d, want %d", got, len(messages))
+	}
+	sort.Strings(messages)
+	return messages
+}
+
+// messages lists the messages of logLines(tag, n)
+func messages(tag string, n int) []string {
+	var result []string
+	for i := 0; i < n; i++ {
+		result = append(result, fmt.Sprintf("%s line %d", tag, i))
+	}
+	return result
+}
+
+// sorted sorts the concatenation of lists
+func sorted(lists ...[]string) []string {
+	var result []string
+	for _, list := range lists {
+		result = append(result, list...)
+	}
+	sort.Strings(result)
+	return result
+}
+
+func TestIncrementalAnalysis(t *testing.T) {
+	tests := []struct {
+		name string
+		// between changes the files after the first run; the second run
+		// must read exactly want
+		between func(t *testing.T, path string)
+		want    []string
+	}{
+		{"nothing new", func(t *testing.T, path string) {}, nil},
+		{"grown", func(t *testing.T, path string) {
+			appendLog(t, path, logLines("more", 4))
+		}, messages("more", 4)},
+		{"partial line", func(t *testing.T, path string) {
+			appendLog(t, path, "2024-01-15 10:01:00 INFO half")
+		}, nil},
+		{"rotated", func(t *testing.T, path string) {
+			appendLog(t, path, logLines("late", 3))
+			rotateLog(t, path, false, logLines("second", 5))
+		}, sorted(messages("late", 3), messages("second", 5))},
+		{"rotated and compressed", func(t *testing.T, path string) {
+			appendLog(t, path, logLines("late", 3))
+			rotateLog(t, path, true, logLines("second", 5))
+		}, sorted(messages("late", 3), messages("second", 5))},
+		{"truncated", func(t *testing.T, path string) {
+			if err := os.WriteFile(path, []byte(logLines("fresh", 2)), 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}, messages("fresh", 2)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, "app.log")
+			statePath := filepath.Join(dir, "state.json")
+			appendLog(t, path, logLines("first", 10))
+
+			if got := analyzeIncremental(t, statePath, path); !reflect.DeepEqual(got, messages("first", 10)) {
+				t.Fatalf("first run read %q", got)
+			}
+			tt.between(t, path)
+			if got := analyzeIncremental(t, statePath, path); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("second run read %q, want %q", got, tt.want)
+			}
+			// Nothing is read twice
+			if got := analyzeIncremental(t, statePath, path); len(got) != 0 {
+				t.Errorf("third run read %q, want nothing", got)
+			}
+		})
+	}
+}
+
+func TestIncrementalHoldsPartialLine(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	statePath := filepath.Join(dir, "state.json")
+	appendLog(t, path, logLines("first", 2)+"2024-01-15 10:01:00 INFO ha")
+
+	if got := analyzeIncremental(t, statePath, path); !reflect.DeepEqual(got, messages("first", 2)) {
+		t.Fatalf("first run read %q, want the complete lines only", got)
+	}
+	appendLog(t, path, "lf done\n")
+	if got := analyzeIncremental(t, statePath, path); !reflect.DeepEqual(got, []string{"half done"}) {
+		t.Errorf("second run read %q, want the completed line once", got)
+	}
+}
+
+func TestWithRenamed(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	appendLog(t, path, logLines("first", 10))
+
+	store, err := checkpoint.Load(filepath.Join(dir, "state.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := NewAnalyzer(&Config{Workers: 1, Checkpoints: store})
+	if err := a.AnalyzeFile(path); err != nil {
+		t.Fatal(err)
+	}
+	if got := a.withRenamed([]string{path}); !reflect.DeepEqual(got, []string{path}) {
+		t.Errorf("withRenamed before rotation = %q", got)
+	}
+
+	rotateLog(t, path, true, logLines("second", 5))
+	want := []string{path + ".1.gz", path}
+	if got := a.withRenamed([]string{path}); !reflect.DeepEqual(got, want) {
+		t.Errorf("withRenamed = %q, want %q", got, want)
+	}
+	// A rotated file already listed is not added again
+	if got := a.withRenamed(want); !reflect.DeepEqual(got, want) {
+		t.Errorf("withRenamed of both = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,121 @@
+package analyzer
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/aadithyaa9/loganalyzer/internal/input"
+	"github.com/aadithyaa9/loganalyzer/internal/models"
+	"github.com/aadithyaa9/loganalyzer/internal/parser"
+)
+
+// Excerpt is the entry found at a line of a file, with the lines around it
+type Excerpt struct {
+	Path       string
+	Target     int // Line asked for
+	Start, End int // First and last line of the entry
+	Lines      []ExcerptLine
+}
+
+// ExcerptLine is one raw line of an excerpt
+type ExcerptLine struct {
+	models.Position
+	Text    string
+	InEntry bool // Part of the entry rather than context
+}
+
+// Locate finds the entry containing line target of a file, decompressing
+// it if needed, and returns it with up to context lines on either side.
+// With multiline assembly on, stack traces and other continuation lines
+// belong to the entry they follow.
+func Locate(path string, target, context int, multiline bool, start *regexp.Regexp) (*Excerpt, error) {
+	if target <= 0 {
+		return nil, fmt.Errorf("line numbers start at 1, got %d", target)
+	}
+
+	file, err := input.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file %s: %w", path, err)
+	}
+	defer file.Close()
+
+	var assembler *parser.MultilineAssembler
+	if multiline {
+		assembler = parser.NewMultilineAssembler(start)
+	}
+
+	var (
+		before  []ExcerptLine // Context preceding the current entry
+		current []ExcerptLine // Lines of the current entry
+		found   *Excerpt
+	)
+	scanner := input.NewLineScanner(file, models.Position{Line: 1})
+	for scanner.Scan() {
+		line := ExcerptLine{Position: scanner.Position(), Text: scanner.Text()}
+
+		if found != nil {
+			// Collecting context after the entry
+			if len(found.Lines) == cap(found.Lines) {
+				break
+			}
+			found.Lines = append(found.Lines, line)
+			continue
+		}
+
+		if startsEntry(assembler, line.Text) {
+			if len(current) > 0 && target < line.Line {
+				found = newExcerpt(path, target, before, current, context)
+				if context == 0 {
+					break
+				}
+				found.Lines = append(found.Lines, line)
+				continue
+			}
+			before = append(before, current...)
+			if len(before) > context {
+				before = before[len(before)-context:]
+			}
+			current = current[:0]
+		}
+		current = append(current, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading file: %w", err)
+	}
+
+	if found == nil {
+		if len(current) == 0 || target > current[len(current)-1].Line {
+			return nil, fmt.Errorf("%s has only %d lines", path, scanner.Position().Line)
+		}
+		found = newExcerpt(path, target, before, current, context)
+	}
+	return found, nil
+}
+
+// startsEntry reports whether a line begins a new entry
+func startsEntry(assembler *parser.MultilineAssembler, line string) bool {
+	if assembler == nil {
+		return true
+	}
+	wasPending := assembler.Pending()
+	_, completed := assembler.Add(line)
+	return completed || (!wasPending && assembler.Pending())
+}
+
+// newExcerpt builds the excerpt for a located entry; its Lines have room
+// for the context still to be read after the entry
+func newExcerpt(path string, target int, before, entry []ExcerptLine, context int) *Excerpt {
+	lines := make([]ExcerptLine, 0, len(before)+len(entry)+context)
+	lines = append(lines, before...)
+	for _, line := range entry {
+		line.InEntry = true
+		lines = append(lines, line)
+	}
+	return &Excerpt{
+		Path:   path,
+		Target: target,
+		Start:  entry[0].Line,
+		End:    entry[len(entry)-1].Line,
+		Lines:  lines,
+	}
+}
@@ -0,0 +1,424 @@
+package analyzer
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aadithyaa9/loganalyzer/internal/models"
+	"github.com/aadithyaa9/loganalyzer/internal/timeexpr"
+)
+
+// QueryError describes a syntax or semantic error in a filter query
+type QueryError struct {
+	Query string
+	Pos   int // Byte offset of the offending token
+	Msg   string
+}
+
+func (e *QueryError) Error() string {
+	return fmt.Sprintf("invalid query at column %d: %s\n  %s\n  %s^",
+		e.Pos+1, e.Msg, e.Query, strings.Repeat(" ", e.Pos))
+}
+
+// ParseQuery compiles a filter expression into a FilterFunc.
+//
+// Grammar:
+//
+//	expr       := term ("or" term)*
+//	term       := factor ("and" factor)*
+//	factor     := "not" factor | "(" expr ")" | comparison
+//	comparison := name op value
+//	name       := level | source | msg | message | raw | time | fields.<path>
+//	op         := = | == | != | > | >= | < | <= | ~ | !~
+//	value      := bare word or "quoted string"
+//
+// Levels compare by severity (entries of UNKNOWN level only match "!="),
+// fields compare numerically when the value is a number, "~" matches a
+// regular expression, and times accept anything --since does. Relative
+// times and naive timestamps use loc.
+//
+// Example: level>=WARN and (source="api.log" or msg~"timeout") and fields.status>=500
+func ParseQuery(query string, loc *time.Location) (FilterFunc, error) {
+	tokens, err := lexQuery(query)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &queryParser{query: query, tokens: tokens, loc: loc, now: time.Now()}
+	filter, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokEOF {
+		return nil, p.errorAt(tok, fmt.Sprintf("unexpected %s", tok))
+	}
+	return filter, nil
+}
+
+// tokenKind classifies query tokens
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokWord
+	tokString
+	tokOp
+	tokLParen
+	tokRParen
+)
+
+// queryToken is a lexed query token
+type queryToken struct {
+	kind  tokenKind
+	value string
+	pos   int
+}
+
+func (t queryToken) String() string {
+	switch t.kind {
+	case tokEOF:
+		return "end of query"
+	case tokString:
+		return strconv.Quote(t.value)
+	default:
+		return fmt.Sprintf("%q", t.value)
+	}
+}
+
+// lexQuery splits a query into tokens
+func lexQuery(query string) ([]queryToken, error) {
+	var tokens []queryToken
+	i := 0
+	for i < len(query) {
+		c := query[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n':
+			i++
+
+		case c == '(':
+			tokens = append(tokens, queryToken{tokLParen, "(", i})
+			i++
+
+		case c == ')':
+			tokens = append(tokens, queryToken{tokRParen, ")", i})
+			i++
+
+		case c == '"' || c == '\'':
+			start := i
+			var b strings.Builder
+			i++
+			for i < len(query) && query[i] != c {
+				if query[i] == '\\' && i+1 < len(query) {
+					i++
+				}
+				b.WriteByte(query[i])
+				i++
+			}
+			if i >= len(query) {
+				return nil, &QueryError{query, start, "unterminated string"}
+			}
+			i++
+			tokens = append(tokens, queryToken{tokString, b.String(), start})
+
+		case strings.IndexByte("=!<>~", c) >= 0:
+			start := i
+			op := string(c)
+			if i+1 < len(query) && (query[i+1] == '=' || (c == '!' && query[i+1] == '~')) {
+				op += string(query[i+1])
+			}
+			switch op {
+			case "=", "==", "!=", ">", ">=", "<", "<=", "~", "!~":
+			default:
+				return nil, &QueryError{query, start, fmt.Sprintf("unknown operator %q", op)}
+			}
+			i += len(op)
+			tokens = append(tokens, queryToken{tokOp, op, start})
+
+		default:
+			start := i
+			for i < len(query) && !strings.ContainsRune(" \t\n()\"'=!<>~", rune(query[i])) {
+				i++
+			}
+			tokens = append(tokens, queryToken{tokWord, query[start:i], start})
+		}
+	}
+
+	tokens = append(tokens, queryToken{tokEOF, "", len(query)})
+	return tokens, nil
+}
+
+// queryParser is a recursive-descent parser over lexed tokens
+type queryParser struct {
+	query  string
+	tokens []queryToken
+	pos    int
+	loc    *time.Location
+	now    time.Time
+}
+
+func (p *queryParser) peek() queryToken {
+	return p.tokens[p.pos]
+}
+
+func (p *queryParser) next() queryToken {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokEOF {
+		p.pos++
+	}
+	return tok
+}
+
+// isKeyword reports whether the next token is the given keyword
+func (p *queryParser) isKeyword(keyword string) bool {
+	tok := p.peek()
+	return tok.kind == tokWord && strings.EqualFold(tok.value, keyword)
+}
+
+func (p *queryParser) errorAt(tok queryToken, msg string) error {
+	return &QueryError{Query: p.query, Pos: tok.pos, Msg: msg}
+}
+
+func (p *queryParser) parseOr() (FilterFunc, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.isKeyword("or") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(entry *models.LogEntry) bool {
+			return l(entry) || right(entry)
+		}
+	}
+	return left, nil
+}
+
+func (p *queryParser) parseAnd() (FilterFunc, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.isKeyword("and") {
+		p.next()
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(entry *models.LogEntry) bool {
+			return l(entry) && right(entry)
+		}
+	}
+	return left, nil
+}
+
+func (p *queryParser) parseNot() (FilterFunc, error) {
+	if p.isKeyword("not") {
+		p.next()
+		inner, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return func(entry *models.LogEntry) bool {
+			return !inner(entry)
+		}, nil
+	}
+
+	if p.peek().kind == tokLParen {
+		open := p.next()
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if tok := p.next(); tok.kind != tokRParen {
+			if tok.kind == tokEOF {
+				return nil, p.errorAt(open, "unclosed parenthesis")
+			}
+			return nil, p.errorAt(tok, fmt.Sprintf("expected ')' but found %s", tok))
+		}
+		return inner, nil
+	}
+
+	return p.parseComparison()
+}
+
+func (p *queryParser) parseComparison() (FilterFunc, error) {
+	nameTok := p.next()
+	if nameTok.kind != tokWord {
+		return nil, p.errorAt(nameTok, fmt.Sprintf("expected a field name but found %s", nameTok))
+	}
+
+	opTok := p.next()
+	if opTok.kind != tokOp {
+		return nil, p.errorAt(opTok, fmt.Sprintf("expected an operator after %q but found %s", nameTok.value, opTok))
+	}
+
+	valueTok := p.next()
+	if valueTok.kind != tokWord && valueTok.kind != tokString {
+		return nil, p.errorAt(valueTok, fmt.Sprintf("expected a value after %q but found %s", opTok.value, valueTok))
+	}
+
+	op, value := opTok.value, valueTok.value
+	if op == "==" {
+		op = "="
+	}
+
+	// Regex operators compile the value once, whatever the field
+	var re *regexp.Regexp
+	if op == "~" || op == "!~" {
+		var err error
+		if re, err = regexp.Compile(value); err != nil {
+			return nil, p.errorAt(valueTok, fmt.Sprintf("invalid regex: %v", err))
+		}
+	}
+
+	name := strings.ToLower(nameTok.value)
+	switch {
+	case name == "level":
+		if re != nil {
+			return stringComparison(op, value, re, func(e *models.LogEntry) (string, bool) {
+				return e.Level.String(), true
+			}), nil
+		}
+		level := models.ParseLogLevel(strings.ToUpper(value))
+		if level == models.UNKNOWN {
+			return nil, p.errorAt(valueTok, fmt.Sprintf("unknown level %q (DEBUG, INFO, WARN, ERROR, FATAL)", value))
+		}
+		// UNKNOWN has no severity, so it only satisfies "!="
+		return orderedComparison(op, func(e *models.LogEntry) (int, bool) {
+			if e.Level == models.UNKNOWN {
+				return 0, false
+			}
+			return compareInts(int(e.Level), int(level)), true
+		}), nil
+
+	case name == "source":
+		return stringComparison(op, value, re, func(e *models.LogEntry) (string, bool) {
+			return e.Source, true
+		}), nil
+
+	case name == "msg" || name == "message":
+		return stringComparison(op, value, re, func(e *models.LogEntry) (string, bool) {
+			return e.Message, true
+		}), nil
+
+	case name == "raw":
+		return stringComparison(op, value, re, func(e *models.LogEntry) (string, bool) {
+			return e.Raw, true
+		}), nil
+
+	case name == "time" || name == "timestamp":
+		if re != nil {
+			return nil, p.errorAt(opTok, "regex match is not supported on time")
+		}
+		t, err := timeexpr.Parse(value, p.now, p.loc)
+		if err != nil {
+			return nil, p.errorAt(valueTok, err.Error())
+		}
+		return orderedComparison(op, func(e *models.LogEntry) (int, bool) {
+			return e.Timestamp.Compare(t), true
+		}), nil
+
+	case strings.HasPrefix(name, "fields."):
+		key := nameTok.value[len("fields."):]
+		if key == "" {
+			return nil, p.errorAt(nameTok, "missing field name after \"fields.\"")
+		}
+		return fieldComparison(key, op, value, re), nil
+
+	default:
+		return nil, p.errorAt(nameTok, fmt.Sprintf("unknown field %q (level, source, msg, raw, time, fields.<name>)", nameTok.value))
+	}
+}
+
+// fieldComparison compares a structured field, numerically if value is a number.
+// A missing field only satisfies the negated operators.
+func fieldComparison(key, op, value string, re *regexp.Regexp) FilterFunc {
+	if number, err := strconv.ParseFloat(value, 64); err == nil && re == nil {
+		return orderedComparison(op, func(e *models.LogEntry) (int, bool) {
+			actual, ok := e.Fields.GetFloat(key)
+			if !ok {
+				return 0, false
+			}
+			switch {
+			case actual < number:
+				return -1, true
+			case actual > number:
+				return 1, true
+			default:
+				return 0, true
+			}
+		})
+	}
+
+	return stringComparison(op, value, re, func(e *models.LogEntry) (string, bool) {
+		return e.Fields.GetString(key)
+	})
+}
+
+// stringComparison compares a string attribute lexically or by regex
+func stringComparison(op, value string, re *regexp.Regexp, get func(*models.LogEntry) (string, bool)) FilterFunc {
+	if re != nil {
+		negate := op == "!~"
+		return func(e *models.LogEntry) bool {
+			actual, ok := get(e)
+			if !ok {
+				return negate
+			}
+			return re.MatchString(actual) != negate
+		}
+	}
+
+	return orderedComparison(op, func(e *models.LogEntry) (int, bool) {
+		actual, ok := get(e)
+		if !ok {
+			return 0, false
+		}
+		return strings.Compare(actual, value), true
+	})
+}
+
+// orderedComparison applies a comparison operator to a three-way compare result
+func orderedComparison(op string, compare func(*models.LogEntry) (int, bool)) FilterFunc {
+	return func(e *models.LogEntry) bool {
+		c, ok := compare(e)
+		if !ok {
+			return op == "!="
+		}
+		switch op {
+		case "=":
+			return c == 0
+		case "!=":
+			return c != 0
+		case ">":
+			return c > 0
+		case ">=":
+			return c >= 0
+		case "<":
+			return c < 0
+		case "<=":
+			return c <= 0
+		default:
+			return false
+		}
+	}
+}
+
+// compareInts returns -1, 0 or 1
+func compareInts(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
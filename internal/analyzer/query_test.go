@@ -0,0 +1,193 @@
+package analyzer
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/aadithyaa9/loganalyzer/internal/models"
+	"github.com/aadithyaa9/loganalyzer/internal/parser"
+)
+
+func queryEntry(level models.LogLevel, source, message string, fields models.Fields) *models.LogEntry {
+	return &models.LogEntry{
+		Timestamp: time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC),
+		Level:     level,
+		Source:    source,
+		Message:   message,
+		Raw:       message,
+		Fields:    fields,
+	}
+}
+
+func TestParseQuery(t *testing.T) {
+	errorAPI := queryEntry(models.ERROR, "api.log", "request timeout", models.Fields{"status": 503.0, "user": "alice"})
+	warnDB := queryEntry(models.WARN, "db.log", "slow query", models.Fields{"status": "42", "user": "bob"})
+	infoAPI := queryEntry(models.INFO, "api.log", "request served", nil)
+	unknown := queryEntry(models.UNKNOWN, "app.log", "no level here", nil)
+	entries := []*models.LogEntry{errorAPI, warnDB, infoAPI, unknown}
+
+	tests := []struct {
+		query string
+		want  []*models.LogEntry
+	}{
+		{"level>=WARN", []*models.LogEntry{errorAPI, warnDB}},
+		{"level>ERROR", nil},
+		{"level<WARN", []*models.LogEntry{infoAPI}},
+		{"level=warn", []*models.LogEntry{warnDB}},
+		{"level!=ERROR", []*models.LogEntry{warnDB, infoAPI, unknown}},
+		{"level~^UNK", []*models.LogEntry{unknown}},
+		{`source="api.log"`, []*models.LogEntry{errorAPI, infoAPI}},
+		{"msg~timeout", []*models.LogEntry{errorAPI}},
+		{"message!~^request", []*models.LogEntry{warnDB, unknown}},
+		{"raw=\"slow query\"", []*models.LogEntry{warnDB}},
+		{"time>=2024-01-15T10:00:00Z", entries},
+		{"time<2024-01-15", nil},
+
+		// Precedence: and binds tighter than or
+		{"source=db.log or source=api.log and level=ERROR", []*models.LogEntry{errorAPI, warnDB}},
+		{"(source=db.log or source=api.log) and level=ERROR", []*models.LogEntry{errorAPI}},
+		{"not level=INFO and source=api.log", []*models.LogEntry{errorAPI}},
+		{"not (level=INFO or level=ERROR)", []*models.LogEntry{warnDB, unknown}},
+		{"not not source=db.log", []*models.LogEntry{warnDB}},
+		{"level=ERROR OR level=WARN", []*models.LogEntry{errorAPI, warnDB}},
+
+		// Fields compare numerically against numbers, as strings otherwise
+		{"fields.status>=500", []*models.LogEntry{errorAPI}},
+		{"fields.status<100", []*models.LogEntry{warnDB}},
+		{"fields.status=42", []*models.LogEntry{warnDB}},
+		{`fields.status>"5"`, []*models.LogEntry{errorAPI, warnDB}},
+		{"fields.user>b", []*models.LogEntry{warnDB}},
+		{"fields.user<b", []*models.LogEntry{errorAPI}},
+		{"fields.user=alice", []*models.LogEntry{errorAPI}},
+		{"fields.user~^b", []*models.LogEntry{warnDB}},
+		{"fields.missing!=x", entries},
+		{"fields.missing=x", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			filter, err := ParseQuery(tt.query, time.UTC)
+			if err != nil {
+				t.Fatalf("ParseQuery(%q): %v", tt.query, err)
+			}
+			var got []*models.LogEntry
+			for _, entry := range entries {
+				if filter(entry) {
+					got = append(got, entry)
+				}
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("matched %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("matched %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestParseQueryErrors(t *testing.T) {
+	tests := []struct {
+		query string
+		pos   int
+	}{
+		{"", 0},
+		{"level", 5},
+		{"level>=", 7},
+		{"level>=LOUD", 7},
+		{"lvl=ERROR", 0},
+		{`msg="open`, 4},
+		{"msg=~x", 4},
+		{"msg~(", 4},
+		{"(level=ERROR", 0},
+		{"(level=ERROR source=x", 13},
+		{"level=ERROR)", 11},
+		{"level=ERROR and", 15},
+		{"time~x", 4},
+		{"time>soon", 5},
+		{"fields.=1", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			_, err := ParseQuery(tt.query, time.UTC)
+			var qe *QueryError
+			if !errors.As(err, &qe) {
+				t.Fatalf("ParseQuery(%q) error = %v, want a QueryError", tt.query, err)
+			}
+			if qe.Pos != tt.pos {
+				t.Errorf("ParseQuery(%q) error at %d, want %d: %v", tt.query, qe.Pos, tt.pos, err)
+			}
+		})
+	}
+}
+
+func TestMinLevelFilter(t *testing.T) {
+	tests := []struct {
+		min   models.LogLevel
+		level models.LogLevel
+		want  bool
+	}{
+		{models.WARN, models.ERROR, true},
+		{models.WARN, models.WARN, true},
+		{models.WARN, models.INFO, false},
+		{models.WARN, models.UNKNOWN, false},
+		{models.FATAL, models.UNKNOWN, false},
+		{models.DEBUG, models.UNKNOWN, true},
+	}
+
+	for _, tt := range tests {
+		entry := queryEntry(tt.level, "app.log", "x", nil)
+		if got := MinLevelFilter(tt.min)(entry); got != tt.want {
+			t.Errorf("MinLevelFilter(%s)(%s) = %v, want %v", tt.min, tt.level, got, tt.want)
+		}
+	}
+}
+
+func TestQueryJSONProfileSourceAndService(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	content := `{"time":1705312800000,"level":50,"name":"orders","msg":"payment failed"}` + "\n" +
+		`{"time":1705312801000,"level":30,"name":"billing","msg":"invoice sent"}` + "\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	profile, err := parser.LoadJSONProfile("pino")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The built-in profiles keep the file as the source and put the
+	// service in fields.service
+	tests := []struct {
+		query    string
+		messages []string
+	}{
+		{`source="app.log"`, []string{"payment failed", "invoice sent"}},
+		{`source="orders"`, nil},
+		{`fields.service="orders"`, []string{"payment failed"}},
+		{`fields.service~"^bill" and level>=INFO`, []string{"invoice sent"}},
+	}
+	for _, tt := range tests {
+		filter, err := ParseQuery(tt.query, time.UTC)
+		if err != nil {
+			t.Fatalf("ParseQuery(%q): %v", tt.query, err)
+		}
+		a := NewAnalyzer(&Config{Workers: 1, Filter: filter, ParserOptions: parser.Options{JSONProfile: profile}})
+		if err := a.AnalyzeFile(path); err != nil {
+			t.Fatal(err)
+		}
+		var messages []string
+		for _, entry := range a.GetResults().GetEntries() {
+			messages = append(messages, entry.Message)
+		}
+		if !slices.Equal(messages, tt.messages) {
+			t.Errorf("%s matched %q, want %q", tt.query, messages, tt.messages)
+		}
+	}
+}
@@ -0,0 +1,200 @@
+package analyzer
+
+import (
+	"container/heap"
+	"sort"
+
+	"github.com/aadithyaa9/loganalyzer/internal/models"
+)
+
+// Sink consumes batches of entries as they flow out of the analysis pipeline.
+// The aggregator calls Consume under its lock, so sinks need no locking.
+type Sink interface {
+	Consume(entries []*models.LogEntry)
+}
+
+// EntryStore is a Sink that retains entries for the reporter
+type EntryStore interface {
+	Sink
+	Entries() []*models.LogEntry // Retained entries (a copy)
+	Len() int                    // Number of retained entries
+	SortByTime()
+	Reset()
+}
+
+// Retention selects which entries the analyzer keeps in memory (enum pattern)
+type Retention int
+
+const (
+	RetainAll    Retention = iota // Every matching entry (json/csv reports)
+	RetainFirstN                  // The N earliest entries (table preview)
+	RetainNone                    // No entries, statistics only
+	RetainHead                    // The first N entries in reading order (context previews)
+)
+
+func (r Retention) String() string {
+	switch r {
+	case RetainAll:
+		return "all"
+	case RetainFirstN:
+		return "first-n"
+	case RetainNone:
+		return "none"
+	case RetainHead:
+		return "head"
+	default:
+		return "unknown"
+	}
+}
+
+// NewEntryStore creates the store for a retention mode
+func NewEntryStore(retention Retention, limit int) EntryStore {
+	switch retention {
+	case RetainFirstN:
+		return NewTopNStore(limit)
+	case RetainNone:
+		return &discardStore{}
+	case RetainHead:
+		return &headStore{limit: limit}
+	default:
+		return &sliceStore{entries: make([]*models.LogEntry, 0)}
+	}
+}
+
+// sliceStore keeps every entry
+type sliceStore struct {
+	entries []*models.LogEntry
+}
+
+func (s *sliceStore) Consume(entries []*models.LogEntry) {
+	s.entries = append(s.entries, entries...)
+}
+
+func (s *sliceStore) Entries() []*models.LogEntry {
+	result := make([]*models.LogEntry, len(s.entries))
+	copy(result, s.entries)
+	return result
+}
+
+func (s *sliceStore) Len() int {
+	return len(s.entries)
+}
+
+func (s *sliceStore) SortByTime() {
+	sort.SliceStable(s.entries, func(i, j int) bool {
+		return s.entries[i].Timestamp.Before(s.entries[j].Timestamp)
+	})
+}
+
+func (s *sliceStore) Reset() {
+	s.entries = make([]*models.LogEntry, 0)
+}
+
+// TopNStore keeps the N earliest entries by timestamp in bounded memory
+type TopNStore struct {
+	limit int
+	heap  latestFirst
+}
+
+// NewTopNStore creates a store that retains at most limit entries
+func NewTopNStore(limit int) *TopNStore {
+	return &TopNStore{limit: limit}
+}
+
+// Consume offers entries, evicting the latest retained one when full
+func (s *TopNStore) Consume(entries []*models.LogEntry) {
+	if s.limit <= 0 {
+		return
+	}
+	for _, entry := range entries {
+		if len(s.heap) < s.limit {
+			heap.Push(&s.heap, entry)
+			continue
+		}
+		if entry.Timestamp.Before(s.heap[0].Timestamp) {
+			s.heap[0] = entry
+			heap.Fix(&s.heap, 0)
+		}
+	}
+}
+
+// Entries returns the retained entries, earliest first
+func (s *TopNStore) Entries() []*models.LogEntry {
+	result := make([]*models.LogEntry, len(s.heap))
+	copy(result, s.heap)
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Timestamp.Before(result[j].Timestamp)
+	})
+	return result
+}
+
+// Len returns the number of retained entries
+func (s *TopNStore) Len() int {
+	return len(s.heap)
+}
+
+// SortByTime is a no-op; Entries always returns sorted entries
+func (s *TopNStore) SortByTime() {}
+
+// Reset drops all retained entries
+func (s *TopNStore) Reset() {
+	s.heap = nil
+}
+
+// latestFirst is a max-heap on timestamp so the latest entry is evicted first
+type latestFirst []*models.LogEntry
+
+func (h latestFirst) Len() int           { return len(h) }
+func (h latestFirst) Less(i, j int) bool { return h[i].Timestamp.After(h[j].Timestamp) }
+func (h latestFirst) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+
+func (h *latestFirst) Push(x any) {
+	*h = append(*h, x.(*models.LogEntry))
+}
+
+func (h *latestFirst) Pop() any {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	*h = old[:n-1]
+	return x
+}
+
+// headStore keeps the first entries it is offered, in order
+type headStore struct {
+	limit   int
+	entries []*models.LogEntry
+}
+
+func (s *headStore) Consume(entries []*models.LogEntry) {
+	if room := s.limit - len(s.entries); room < len(entries) {
+		entries = entries[:max(room, 0)]
+	}
+	s.entries = append(s.entries, entries...)
+}
+
+func (s *headStore) Entries() []*models.LogEntry {
+	result := make([]*models.LogEntry, len(s.entries))
+	copy(result, s.entries)
+	return result
+}
+
+func (s *headStore) Len() int {
+	return len(s.entries)
+}
+
+// SortByTime is a no-op; the head keeps reading order
+func (s *headStore) SortByTime() {}
+
+func (s *headStore) Reset() {
+	s.entries = nil
+}
+
+// discardStore retains nothing, for statistics-only runs
+type discardStore struct{}
+
+func (s *discardStore) Consume(entries []*models.LogEntry) {}
+func (s *discardStore) Entries() []*models.LogEntry       { return nil }
+func (s *discardStore) Len() int                          { return 0 }
+func (s *discardStore) SortByTime()                       {}
+func (s *discardStore) Reset()                            {}
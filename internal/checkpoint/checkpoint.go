@@ -0,0 +1,132 @@
+package checkpoint
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/aadithyaa9/loganalyzer/internal/input"
+	"github.com/aadithyaa9/loganalyzer/internal/models"
+)
+
+// fingerprintSize is how many leading bytes of content identify a file
+const fingerprintSize = 1024
+
+// Checkpoint records how far a file was read and which file it was, so a
+// later run can tell a file that grew from one that was rotated or replaced
+type Checkpoint struct {
+	Device uint64 `json:"device,omitempty"` // 0 where the platform has no inodes
+	Inode  uint64 `json:"inode,omitempty"`
+
+	// SHA-256 of the first FingerprintSize bytes of (decompressed) content
+	Fingerprint     string `json:"fingerprint"`
+	FingerprintSize int    `json:"fingerprint_size"`
+
+	Offset  int64     `json:"offset"` // Content bytes read
+	Line    int       `json:"line"`   // Number of the next line to read
+	Updated time.Time `json:"updated"`
+}
+
+// Position returns where reading continues
+func (c Checkpoint) Position() models.Position {
+	return models.Position{Line: c.Line, Offset: c.Offset}
+}
+
+// Take checkpoints an open, uncompressed file read up to pos. The file's
+// own identity is recorded, even if its path now names another file.
+func Take(file *os.File, pos models.Position) (Checkpoint, error) {
+	info, err := file.Stat()
+	if err != nil {
+		return Checkpoint{}, fmt.Errorf("failed to stat %s: %w", file.Name(), err)
+	}
+	head := make([]byte, fingerprintSize)
+	n, err := file.ReadAt(head, 0)
+	if err != nil && err != io.EOF {
+		return Checkpoint{}, fmt.Errorf("failed to read %s: %w", file.Name(), err)
+	}
+	return newCheckpoint(info, head[:n], pos), nil
+}
+
+// TakePath checkpoints the file at path, decompressing it if needed, read up to pos
+func TakePath(path string, pos models.Position) (Checkpoint, error) {
+	h, err := readHead(path)
+	if err != nil {
+		return Checkpoint{}, err
+	}
+	return newCheckpoint(h.info, h.data, pos), nil
+}
+
+func newCheckpoint(info os.FileInfo, head []byte, pos models.Position) Checkpoint {
+	device, inode := fileID(info)
+	return Checkpoint{
+		Device:          device,
+		Inode:           inode,
+		Fingerprint:     fingerprint(head),
+		FingerprintSize: len(head),
+		Offset:          pos.Offset,
+		Line:            pos.Line,
+		Updated:         time.Now(),
+	}
+}
+
+// head is what identifies the file at a path
+type head struct {
+	info        os.FileInfo
+	data        []byte // Up to fingerprintSize leading bytes of content
+	compressed  bool
+	contentSize int64 // Size of the content, -1 for archives
+}
+
+// readHead reads the identity of the file at path
+func readHead(path string) (head, error) {
+	file, err := input.Open(path)
+	if err != nil {
+		return head{}, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer file.Close()
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return head{}, fmt.Errorf("failed to stat %s: %w", path, err)
+	}
+	data := make([]byte, fingerprintSize)
+	n, err := io.ReadFull(file, data)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return head{}, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	h := head{info: info, data: data[:n], compressed: file.Compression != input.None, contentSize: -1}
+	if !h.compressed {
+		h.contentSize = info.Size()
+	}
+	return h, nil
+}
+
+// matches reports whether a checkpoint was taken of this file. Inodes must
+// agree where both are known, except for archives, which are new files
+// holding old content; the content must start as it did.
+func (h head) matches(c Checkpoint) bool {
+	device, inode := fileID(h.info)
+	sameInode := c.Inode != 0 && inode != 0 && c.Inode == inode && c.Device == device
+	if c.Inode != 0 && inode != 0 && !h.compressed && !sameInode {
+		return false
+	}
+	if c.FingerprintSize == 0 {
+		// An empty file has no content to compare
+		return sameInode
+	}
+	if len(h.data) < c.FingerprintSize || fingerprint(h.data[:c.FingerprintSize]) != c.Fingerprint {
+		return false
+	}
+	// A file shorter than what was read was truncated and rewritten
+	return h.contentSize < 0 || h.contentSize >= c.Offset
+}
+
+// fingerprint hashes leading content bytes
+func fingerprint(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,253 @@
+package checkpoint
+
+import (
+	"compress/gzip"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aadithyaa9/loganalyzer/internal/models"
+)
+
+// logLines returns n log lines tagged so files of different generations
+// differ from their first byte on
+func logLines(tag string, n int) string {
+	var b strings.Builder
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&b, "2024-01-15 10:00:%02d INFO %s line %d\n", i%60, tag, i)
+	}
+	return b.String()
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func appendFile(t *testing.T, path, content string) {
+	t.Helper()
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	if _, err := file.WriteString(content); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// gzipFile compresses path to path+".gz" and removes path, like logrotate
+func gzipFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file, err := os.Create(path + ".gz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := gzip.NewWriter(file)
+	if _, err := w.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := file.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	return path + ".gz"
+}
+
+// checkpointEnd checkpoints path as read to its end
+func checkpointEnd(t *testing.T, store *Store, path string) Checkpoint {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pos := models.Position{Line: strings.Count(string(data), "\n") + 1, Offset: int64(len(data))}
+	c, err := TakePath(path, pos)
+	if err != nil {
+		t.Fatalf("TakePath: %v", err)
+	}
+	store.Set(path, c)
+	return c
+}
+
+func TestLookup(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(t *testing.T, path string)
+		want   bool
+	}{
+		{"unchanged", func(t *testing.T, path string) {}, true},
+		{"grown", func(t *testing.T, path string) {
+			appendFile(t, path, logLines("new", 5))
+		}, true},
+		{"truncated and rewritten", func(t *testing.T, path string) {
+			writeFile(t, path, logLines("old", 3))
+		}, false},
+		{"head rewritten in place", func(t *testing.T, path string) {
+			writeFile(t, path, logLines("new", 20))
+		}, false},
+		{"replaced by another inode with the same content", func(t *testing.T, path string) {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			tmp := path + ".tmp"
+			writeFile(t, tmp, string(data))
+			if err := os.Rename(tmp, path); err != nil {
+				t.Fatal(err)
+			}
+		}, !inodesKnown(t)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "app.log")
+			writeFile(t, path, logLines("old", 20))
+			store, err := Load(filepath.Join(t.TempDir(), "state.json"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := checkpointEnd(t, store, path)
+
+			tt.change(t, path)
+			got, ok := store.Lookup(path)
+			if ok != tt.want {
+				t.Fatalf("Lookup ok = %v, want %v", ok, tt.want)
+			}
+			if ok && got.Position() != want.Position() {
+				t.Errorf("Lookup position = %+v, want %+v", got.Position(), want.Position())
+			}
+		})
+	}
+}
+
+func TestLookupEmptyFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	writeFile(t, path, "")
+	store, err := Load(filepath.Join(t.TempDir(), "state.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkpointEnd(t, store, path)
+
+	// Without content, only the inode tells the file apart
+	if _, ok := store.Lookup(path); ok != inodesKnown(t) {
+		t.Errorf("Lookup ok = %v, want %v", ok, inodesKnown(t))
+	}
+}
+
+func TestRenamed(t *testing.T) {
+	tests := []struct {
+		name   string
+		rotate func(t *testing.T, path string) string // Returns the rotated file
+	}{
+		{"renamed", func(t *testing.T, path string) string {
+			if err := os.Rename(path, path+".1"); err != nil {
+				t.Fatal(err)
+			}
+			return path + ".1"
+		}},
+		{"renamed and compressed", func(t *testing.T, path string) string {
+			if err := os.Rename(path, path+".1"); err != nil {
+				t.Fatal(err)
+			}
+			return gzipFile(t, path+".1")
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, "app.log")
+			writeFile(t, path, logLines("first", 20))
+			writeFile(t, filepath.Join(dir, "app.log.2"), logLines("older", 20))
+			store, err := Load(filepath.Join(t.TempDir(), "state.json"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := checkpointEnd(t, store, path)
+
+			if _, ok := store.Renamed(path); ok {
+				t.Fatalf("Renamed before rotation reported a rotation")
+			}
+
+			// Lines written after the checkpoint, then rotation
+			appendFile(t, path, logLines("late", 5))
+			rotated := tt.rotate(t, path)
+			writeFile(t, path, logLines("second", 10))
+
+			got, ok := store.Renamed(path)
+			if !ok || got != rotated {
+				t.Fatalf("Renamed = %q, %v, want %q", got, ok, rotated)
+			}
+			if c, ok := store.Lookup(rotated); !ok || c.Position() != want.Position() {
+				t.Errorf("Lookup(rotated) = %+v, %v, want %+v", c.Position(), ok, want.Position())
+			}
+			if _, ok := store.Lookup(path); ok {
+				t.Errorf("Lookup(new file) found a checkpoint, want none")
+			}
+		})
+	}
+}
+
+func TestSaveLoad(t *testing.T) {
+	dir := t.TempDir()
+	kept := filepath.Join(dir, "kept.log")
+	gone := filepath.Join(dir, "gone.log")
+	writeFile(t, kept, logLines("kept", 3))
+	writeFile(t, gone, logLines("gone", 3))
+
+	statePath := filepath.Join(dir, "state", "state.json")
+	store, err := Load(statePath)
+	if err != nil {
+		t.Fatalf("Load of a missing state file: %v", err)
+	}
+	want := checkpointEnd(t, store, kept)
+	checkpointEnd(t, store, gone)
+	if err := os.Remove(gone); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	loaded, err := Load(statePath)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(loaded.files) != 1 {
+		t.Errorf("loaded %d checkpoints, want 1 (the missing file forgotten)", len(loaded.files))
+	}
+	if c, ok := loaded.Lookup(kept); !ok || c.Position() != want.Position() || c.Fingerprint != want.Fingerprint {
+		t.Errorf("Lookup after Load = %+v, %v, want %+v", c, ok, want)
+	}
+
+	writeFile(t, statePath, `{"version": 99, "files": {}}`)
+	if _, err := Load(statePath); err == nil {
+		t.Errorf("Load of another version succeeded, want an error")
+	}
+}
+
+// inodesKnown reports whether this platform identifies files by inode
+func inodesKnown(t *testing.T) bool {
+	t.Helper()
+	info, err := os.Stat(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, inode := fileID(info)
+	return inode != 0
+}
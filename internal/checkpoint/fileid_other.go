@@ -0,0 +1,10 @@
+//go:build !unix
+
+package checkpoint
+
+import "os"
+
+// fileID returns 0, 0: files are identified by their content alone
+func fileID(info os.FileInfo) (uint64, uint64) {
+	return 0, 0
+}
@@ -0,0 +1,163 @@
+package checkpoint
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/aadithyaa9/loganalyzer/internal/input"
+)
+
+// stateVersion is the format version of state files
+const stateVersion = 1
+
+// stateFile is the on-disk form of a Store
+type stateFile struct {
+	Version int                   `json:"version"`
+	Files   map[string]Checkpoint `json:"files"`
+}
+
+// Store holds the checkpoints of a state file, keyed by absolute path
+// (thread-safe)
+type Store struct {
+	path  string
+	mu    sync.Mutex
+	files map[string]Checkpoint
+}
+
+// Load reads a state file; a missing file is an empty store
+func Load(path string) (*Store, error) {
+	s := &Store{path: path, files: make(map[string]Checkpoint)}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state file: %w", err)
+	}
+
+	var state stateFile
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("invalid state file %s: %w", path, err)
+	}
+	if state.Version != stateVersion {
+		return nil, fmt.Errorf("state file %s has version %d, expected %d", path, state.Version, stateVersion)
+	}
+	if state.Files != nil {
+		s.files = state.Files
+	}
+	return s, nil
+}
+
+// Lookup returns the checkpoint of the file now at path: the path's own
+// while it still names the file it was taken of, or else one taken under
+// another path of a file since renamed to this one by rotation
+func (s *Store) Lookup(path string) (Checkpoint, bool) {
+	key := absPath(path)
+	h, err := readHead(path)
+	if err != nil {
+		return Checkpoint{}, false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if c, ok := s.files[key]; ok && h.matches(c) {
+		return c, true
+	}
+	var found Checkpoint
+	ok := false
+	for other, c := range s.files {
+		if other != key && h.matches(c) && (!ok || c.Updated.After(found.Updated)) {
+			found, ok = c, true
+		}
+	}
+	return found, ok
+}
+
+// Renamed returns the rotated sibling (app.log.1, app.log.1.gz, ...) holding
+// the file path named when it was checkpointed, when path now names a new
+// file. Lines written to the old file after the checkpoint are still there.
+func (s *Store) Renamed(path string) (string, bool) {
+	s.mu.Lock()
+	c, ok := s.files[absPath(path)]
+	s.mu.Unlock()
+	if !ok {
+		return "", false
+	}
+	if h, err := readHead(path); err == nil && h.matches(c) {
+		return "", false // Not rotated
+	}
+
+	siblings, err := input.RotationSet(path)
+	if err != nil {
+		return "", false
+	}
+	// Newest first: the most recent rotation is the likely one
+	for i := len(siblings) - 1; i >= 0; i-- {
+		if absPath(siblings[i]) == absPath(path) {
+			continue
+		}
+		if h, err := readHead(siblings[i]); err == nil && h.matches(c) {
+			return siblings[i], true
+		}
+	}
+	return "", false
+}
+
+// Set records the checkpoint of path
+func (s *Store) Set(path string, c Checkpoint) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.files[absPath(path)] = c
+}
+
+// Save writes the state file atomically, forgetting files that no longer exist
+func (s *Store) Save() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for path := range s.files {
+		if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+			delete(s.files, path)
+		}
+	}
+
+	data, err := json.MarshalIndent(stateFile{Version: stateVersion, Files: s.files}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode state: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+
+	// Write a temporary file and rename it, so a crash never leaves a torn state file
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+	return nil
+}
+
+// absPath returns the key of a path
+func absPath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return filepath.Clean(path)
+}
@@ -0,0 +1,114 @@
+package input
+
+import (
+	"bufio"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/aadithyaa9/loganalyzer/internal/models"
+)
+
+// maxLineSize bounds the length of a single log line
+const maxLineSize = 1024 * 1024
+
+// LineScanner reads lines like bufio.Scanner and reports where each one
+// starts in the file
+type LineScanner struct {
+	scanner *bufio.Scanner
+	pos     models.Position // Position of the current line
+	next    models.Position // Position of the line after it
+	advance int             // Bytes consumed by the last token, terminator included
+	partial bool            // Whether the last token ended without a terminator
+}
+
+// NewLineScanner scans r, whose first line is at start. A start line of 0
+// means the line numbers are unknown; they then stay 0.
+func NewLineScanner(r io.Reader, start models.Position) *LineScanner {
+	s := &LineScanner{next: start}
+	s.scanner = bufio.NewScanner(r)
+	// Increase buffer size for large log lines
+	s.scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+	s.scanner.Split(s.split)
+	return s
+}
+
+// split is bufio.ScanLines, recording how many bytes each line used
+func (s *LineScanner) split(data []byte, atEOF bool) (int, []byte, error) {
+	advance, token, err := bufio.ScanLines(data, atEOF)
+	if token != nil {
+		s.advance = advance
+		s.partial = advance == 0 || data[advance-1] != '\n'
+	}
+	return advance, token, err
+}
+
+// Scan advances to the next line
+func (s *LineScanner) Scan() bool {
+	if !s.scanner.Scan() {
+		return false
+	}
+	s.pos = s.next
+	if s.next.Line > 0 {
+		s.next.Line++
+	}
+	s.next.Offset += int64(s.advance)
+	return true
+}
+
+// Text returns the current line without its terminator
+func (s *LineScanner) Text() string {
+	return s.scanner.Text()
+}
+
+// Position returns where the current line starts
+func (s *LineScanner) Position() models.Position {
+	return s.pos
+}
+
+// Next returns where the line after the current one starts
+func (s *LineScanner) Next() models.Position {
+	return s.next
+}
+
+// Partial reports whether the current line ran into the end of the input
+// without a terminator, as a line still being written does
+func (s *LineScanner) Partial() bool {
+	return s.partial
+}
+
+// Err returns the first non-EOF error
+func (s *LineScanner) Err() error {
+	return s.scanner.Err()
+}
+
+// SourceNames gives each path a short display name: its base name, or
+// when several paths share a base name, the shortest trailing part of the
+// path that tells them apart (api/app.log and worker/app.log)
+func SourceNames(paths []string) map[string]string {
+	groups := make(map[string][][]string)
+	for _, path := range paths {
+		parts := strings.Split(filepath.ToSlash(filepath.Clean(path)), "/")
+		base := parts[len(parts)-1]
+		groups[base] = append(groups[base], parts)
+	}
+
+	names := make(map[string]string, len(paths))
+	for _, path := range paths {
+		parts := strings.Split(filepath.ToSlash(filepath.Clean(path)), "/")
+		depth := 1
+		for _, other := range groups[parts[len(parts)-1]] {
+			// Grow the suffix until it differs from the other path's
+			shared := 0
+			for shared < len(parts) && shared < len(other) &&
+				parts[len(parts)-1-shared] == other[len(other)-1-shared] {
+				shared++
+			}
+			if shared < len(parts) && shared+1 > depth {
+				depth = shared + 1
+			}
+		}
+		names[path] = strings.Join(parts[len(parts)-depth:], "/")
+	}
+	return names
+}
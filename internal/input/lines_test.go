@@ -0,0 +1,57 @@
+package input
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/aadithyaa9/loganalyzer/internal/models"
+)
+
+func TestLineScannerPositions(t *testing.T) {
+	tests := []struct {
+		name  string
+		start models.Position
+		want  []models.Position
+		next  models.Position
+	}{
+		{
+			name:  "from the start",
+			start: models.Position{Line: 1},
+			want:  []models.Position{{Line: 1, Offset: 0}, {Line: 2, Offset: 2}, {Line: 3, Offset: 6}},
+			next:  models.Position{Line: 4, Offset: 7},
+		},
+		{
+			name:  "resumed",
+			start: models.Position{Line: 10, Offset: 100},
+			want:  []models.Position{{Line: 10, Offset: 100}, {Line: 11, Offset: 102}, {Line: 12, Offset: 106}},
+			next:  models.Position{Line: 13, Offset: 107},
+		},
+		{
+			name:  "unknown line numbers",
+			start: models.Position{Offset: 100},
+			want:  []models.Position{{Offset: 100}, {Offset: 102}, {Offset: 106}},
+			next:  models.Position{Offset: 107},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scanner := NewLineScanner(strings.NewReader("a\nbc\r\nd"), tt.start)
+			var got []models.Position
+			for scanner.Scan() {
+				got = append(got, scanner.Position())
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("scanned %d lines, want %d", len(got), len(tt.want))
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("line %d at %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+			if scanner.Next() != tt.next {
+				t.Errorf("Next() = %+v, want %+v", scanner.Next(), tt.next)
+			}
+		})
+	}
+}
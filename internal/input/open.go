@@ -0,0 +1,182 @@
+package input
+
+import (
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/ulikunitz/xz"
+)
+
+// Compression represents the compression of an input file (enum pattern)
+type Compression int
+
+const (
+	None Compression = iota
+	Gzip
+	Bzip2
+	Zstd
+	Xz
+)
+
+func (c Compression) String() string {
+	switch c {
+	case None:
+		return "none"
+	case Gzip:
+		return "gzip"
+	case Bzip2:
+		return "bzip2"
+	case Zstd:
+		return "zstd"
+	case Xz:
+		return "xz"
+	default:
+		return "unknown"
+	}
+}
+
+// Magic byte prefixes of the supported formats
+var (
+	gzipMagic  = []byte{0x1f, 0x8b}
+	bzip2Magic = []byte("BZh")
+	zstdMagic  = []byte{0x28, 0xb5, 0x2f, 0xfd}
+	xzMagic    = []byte{0xfd, '7', 'z', 'X', 'Z', 0x00}
+)
+
+// A bzip2 stream continues its magic with a block size digit and the magic
+// of its first block, or of the stream end when empty
+var (
+	bzip2BlockMagic = []byte{0x31, 0x41, 0x59, 0x26, 0x53, 0x59}
+	bzip2EndMagic   = []byte{0x17, 0x72, 0x45, 0x38, 0x50, 0x90}
+)
+
+// headerSize is how many leading bytes Detect needs
+const headerSize = 10
+
+// Detect identifies the compression from the first bytes of a file
+func Detect(header []byte) Compression {
+	switch {
+	case bytes.HasPrefix(header, gzipMagic):
+		return Gzip
+	case isBzip2(header):
+		return Bzip2
+	case bytes.HasPrefix(header, zstdMagic):
+		return Zstd
+	case bytes.HasPrefix(header, xzMagic):
+		return Xz
+	default:
+		return None
+	}
+}
+
+// isBzip2 checks the whole bzip2 header, so that text starting with "BZh"
+// is not taken for an archive
+func isBzip2(header []byte) bool {
+	if len(header) < headerSize || !bytes.HasPrefix(header, bzip2Magic) {
+		return false
+	}
+	if header[3] < '1' || header[3] > '9' {
+		return false
+	}
+	return bytes.Equal(header[4:10], bzip2BlockMagic) || bytes.Equal(header[4:10], bzip2EndMagic)
+}
+
+// File is an opened input, decompressed transparently if needed
+type File struct {
+	io.Reader
+	Compression Compression
+	Size        int64 // Size on disk (compressed size for archives)
+
+	file   *os.File
+	closer func()
+}
+
+// Close closes the decompressor and the underlying file
+func (f *File) Close() error {
+	if f.closer != nil {
+		f.closer()
+	}
+	return f.file.Close()
+}
+
+// Open opens a log file, sniffing magic bytes to pick a decompressor
+func Open(path string) (*File, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to get file info: %w", err)
+	}
+
+	br := bufio.NewReaderSize(file, 64*1024)
+	header, _ := br.Peek(headerSize)
+
+	f := &File{
+		Reader:      br,
+		Compression: Detect(header),
+		Size:        info.Size(),
+		file:        file,
+	}
+
+	switch f.Compression {
+	case Gzip:
+		gz, err := gzip.NewReader(br)
+		if err != nil {
+			file.Close()
+			return nil, fmt.Errorf("failed to open gzip stream: %w", err)
+		}
+		f.Reader = gz
+		f.closer = func() { gz.Close() }
+
+	case Bzip2:
+		f.Reader = bzip2.NewReader(br)
+
+	case Zstd:
+		zr, err := zstd.NewReader(br)
+		if err != nil {
+			file.Close()
+			return nil, fmt.Errorf("failed to open zstd stream: %w", err)
+		}
+		f.Reader = zr
+		f.closer = zr.Close
+
+	case Xz:
+		xr, err := xz.NewReader(br)
+		if err != nil {
+			file.Close()
+			return nil, fmt.Errorf("failed to open xz stream: %w", err)
+		}
+		f.Reader = xr
+	}
+
+	return f, nil
+}
+
+// Skip discards the first n bytes of content, seeking in uncompressed files
+// and decompressing past them in archives
+func (f *File) Skip(n int64) error {
+	if n <= 0 {
+		return nil
+	}
+	if f.Compression == None {
+		if _, err := f.file.Seek(n, io.SeekStart); err != nil {
+			return fmt.Errorf("failed to seek: %w", err)
+		}
+		f.Reader = bufio.NewReaderSize(f.file, 64*1024)
+		return nil
+	}
+	if _, err := io.CopyN(io.Discard, f.Reader, n); err != nil {
+		return fmt.Errorf("failed to skip %d bytes: %w", n, err)
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package input
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/ulikunitz/xz"
+)
+
+const content = "hello\nworld\n"
+
+// bzip2Hello is content compressed by bzip2 (no encoder in the standard library)
+var bzip2Hello = []byte{
+	0x42, 0x5a, 0x68, 0x39, 0x31, 0x41, 0x59, 0x26, 0x53, 0x59, 0x6b, 0x5f,
+	0xb1, 0xdd, 0x00, 0x00, 0x02, 0x41, 0x80, 0x00, 0x10, 0x06, 0x44, 0x90,
+	0x80, 0x20, 0x00, 0x31, 0x0c, 0x08, 0x21, 0xa3, 0x69, 0x08, 0x07, 0x23,
+	0xae, 0x87, 0x8b, 0xb9, 0x22, 0x9c, 0x28, 0x48, 0x35, 0xaf, 0xd8, 0xee,
+	0x80,
+}
+
+func compress(t *testing.T, compression Compression, data string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	var w io.WriteCloser
+	var err error
+	switch compression {
+	case None:
+		return []byte(data)
+	case Bzip2:
+		if data != content {
+			t.Fatalf("bzip2 test data is fixed to %q", content)
+		}
+		return bzip2Hello
+	case Gzip:
+		w = gzip.NewWriter(&buf)
+	case Zstd:
+		w, err = zstd.NewWriter(&buf)
+	case Xz:
+		w, err = xz.NewWriter(&buf)
+	}
+	if err != nil {
+		t.Fatalf("creating %s writer: %v", compression, err)
+	}
+	if _, err := io.WriteString(w, data); err != nil {
+		t.Fatalf("writing %s: %v", compression, err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("closing %s writer: %v", compression, err)
+	}
+	return buf.Bytes()
+}
+
+func TestOpen(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        []byte
+		compression Compression
+		want        string
+	}{
+		{"plain", []byte(content), None, content},
+		{"gzip", nil, Gzip, content},
+		{"bzip2", nil, Bzip2, content},
+		{"zstd", nil, Zstd, content},
+		{"xz", nil, Xz, content},
+		{"text starting with BZh", []byte("BZh is not an archive\n"), None, "BZh is not an archive\n"},
+		{"BZh with a block size but no block magic", []byte("BZh9 looks close\n"), None, "BZh9 looks close\n"},
+		{"short file", []byte("BZ"), None, "BZ"},
+		{"empty file", []byte{}, None, ""},
+	}
+
+	dir := t.TempDir()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := tt.data
+			if data == nil {
+				data = compress(t, tt.compression, tt.want)
+			}
+			path := filepath.Join(dir, tt.name)
+			if err := os.WriteFile(path, data, 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			f, err := Open(path)
+			if err != nil {
+				t.Fatalf("Open: %v", err)
+			}
+			defer f.Close()
+			if f.Compression != tt.compression {
+				t.Errorf("Compression = %s, want %s", f.Compression, tt.compression)
+			}
+			got, err := io.ReadAll(f)
+			if err != nil {
+				t.Fatalf("reading: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("content = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSkip(t *testing.T) {
+	dir := t.TempDir()
+	for _, compression := range []Compression{None, Gzip, Bzip2} {
+		t.Run(compression.String(), func(t *testing.T) {
+			path := filepath.Join(dir, compression.String())
+			if err := os.WriteFile(path, compress(t, compression, content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			f, err := Open(path)
+			if err != nil {
+				t.Fatalf("Open: %v", err)
+			}
+			defer f.Close()
+
+			if err := f.Skip(int64(len("hello\n"))); err != nil {
+				t.Fatalf("Skip: %v", err)
+			}
+			got, _ := io.ReadAll(f)
+			if string(got) != "world\n" {
+				t.Errorf("after Skip: %q, want %q", got, "world\n")
+			}
+		})
+	}
+}
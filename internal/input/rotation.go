@@ -0,0 +1,135 @@
+package input
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// rotatedName splits a log file name into its base name and rotation suffix:
+//
+//	app.log                  base "app.log"
+//	app.log.3, app.log.3.gz  base "app.log", index 3
+//	app.log-20240120.gz      base "app.log", date 20240120
+//	app.log.gz               base "app.log", archived without index
+//	messages-20240120        base "messages" (syslog files have no extension)
+var rotatedName = regexp.MustCompile(
+	`^(.+\.log|syslog|messages|secure|maillog)` +
+		`(?:\.(\d+)|-(\d{4}-?\d{2}-?\d{2}(?:-?\d{2,6})?)|\.(\d{4}-\d{2}-\d{2}))?` +
+		`(?:\.(gz|bz2|zst|xz))?$`)
+
+// rotationInfo describes where a file sits within its rotation set
+type rotationInfo struct {
+	base  string // e.g. "app.log"
+	index int    // logrotate ".N" suffix, -1 if absent
+	date  string // dateext suffix with separators removed, "" if absent
+	ext   string // compression extension, "" if absent
+}
+
+// parseRotation parses a file name, reporting false if it is not a log file
+func parseRotation(name string) (rotationInfo, bool) {
+	m := rotatedName.FindStringSubmatch(name)
+	if m == nil {
+		return rotationInfo{}, false
+	}
+
+	info := rotationInfo{base: m[1], index: -1, ext: m[5]}
+	if m[2] != "" {
+		info.index, _ = strconv.Atoi(m[2])
+	}
+	switch {
+	case m[3] != "":
+		info.date = strings.ReplaceAll(m[3], "-", "")
+	case m[4] != "":
+		info.date = strings.ReplaceAll(m[4], "-", "")
+	}
+	return info, true
+}
+
+// rank orders files in a set from oldest to newest: dated archives by date,
+// then numbered ones from highest index down, then un-numbered archives,
+// and finally the live file itself
+func (r rotationInfo) rank() (int, int, string) {
+	switch {
+	case r.date != "":
+		return 0, 0, r.date
+	case r.index >= 0:
+		return 1, -r.index, ""
+	case r.ext != "":
+		return 2, 0, ""
+	default:
+		return 3, 0, ""
+	}
+}
+
+// IsLogFile reports whether a file name is a live or rotated log file
+func IsLogFile(name string) bool {
+	_, ok := parseRotation(filepath.Base(name))
+	return ok
+}
+
+// IsLiveLogFile reports whether a file name is a log file still being
+// written, not a rotated or compressed sibling
+func IsLiveLogFile(name string) bool {
+	info, ok := parseRotation(filepath.Base(name))
+	return ok && info.index < 0 && info.date == "" && info.ext == ""
+}
+
+// RotationSet returns the live file and all its rotated siblings, oldest first
+func RotationSet(path string) ([]string, error) {
+	info, ok := parseRotation(filepath.Base(path))
+	if !ok {
+		return nil, fmt.Errorf("%s does not look like a log file (*.log, syslog, messages)", path)
+	}
+
+	dir := filepath.Dir(path)
+	dirEntries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read directory %s: %w", dir, err)
+	}
+
+	var files []string
+	for _, de := range dirEntries {
+		if de.IsDir() {
+			continue
+		}
+		if r, ok := parseRotation(de.Name()); ok && r.base == info.base {
+			files = append(files, filepath.Join(dir, de.Name()))
+		}
+	}
+
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no files found for rotation set %s", path)
+	}
+
+	SortChronologically(files)
+	return files, nil
+}
+
+// SortChronologically groups files by rotation set and orders each set oldest first
+func SortChronologically(files []string) {
+	sort.SliceStable(files, func(i, j int) bool {
+		ri, _ := parseRotation(filepath.Base(files[i]))
+		rj, _ := parseRotation(filepath.Base(files[j]))
+
+		si := filepath.Join(filepath.Dir(files[i]), ri.base)
+		sj := filepath.Join(filepath.Dir(files[j]), rj.base)
+		if si != sj {
+			return si < sj
+		}
+
+		ci, ni, di := ri.rank()
+		cj, nj, dj := rj.rank()
+		if ci != cj {
+			return ci < cj
+		}
+		if di != dj {
+			return di < dj
+		}
+		return ni < nj
+	})
+}
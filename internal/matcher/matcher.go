@@ -0,0 +1,242 @@
+package matcher
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/aadithyaa9/loganalyzer/internal/models"
+)
+
+// MatchMode represents how a pattern is interpreted (enum pattern)
+type MatchMode int
+
+const (
+	Literal MatchMode = iota
+	Regex
+	Glob
+)
+
+func (m MatchMode) String() string {
+	switch m {
+	case Literal:
+		return "literal"
+	case Regex:
+		return "regex"
+	case Glob:
+		return "glob"
+	default:
+		return "unknown"
+	}
+}
+
+// Matcher is a pattern compiled once and applied to many lines
+type Matcher interface {
+	Match(s string) bool
+	String() string
+}
+
+// Compile builds a Matcher for the pattern in the given mode.
+// ignoreCase applies to regex and glob modes as well as literal ones.
+// A glob with wildcards must match the whole message; one without any is
+// a substring search like a literal pattern.
+func Compile(pattern string, mode MatchMode, ignoreCase bool) (Matcher, error) {
+	if mode == Glob && !hasWildcard(pattern) {
+		mode = Literal
+		pattern = unescapeGlob(pattern)
+	}
+
+	switch mode {
+	case Literal:
+		if ignoreCase {
+			return &foldMatcher{pattern: pattern, lower: strings.ToLower(pattern)}, nil
+		}
+		return NewLiteral(pattern), nil
+
+	case Regex:
+		expr := pattern
+		if ignoreCase {
+			expr = "(?i)" + expr
+		}
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regex %q: %w", pattern, err)
+		}
+		return &regexMatcher{pattern: pattern, re: re}, nil
+
+	case Glob:
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid glob %q: %w", pattern, err)
+		}
+		re, err := regexp.Compile(globToRegex(pattern, ignoreCase))
+		if err != nil {
+			return nil, fmt.Errorf("invalid glob %q: %w", pattern, err)
+		}
+		return &regexMatcher{pattern: pattern, re: re}, nil
+
+	default:
+		return nil, fmt.Errorf("unknown match mode: %s", mode)
+	}
+}
+
+// NewLiteral returns a case-sensitive substring matcher
+func NewLiteral(pattern string) Matcher {
+	return &literalMatcher{pattern: pattern}
+}
+
+// MatchEntry checks the entry's message and raw line against the matcher
+func MatchEntry(m Matcher, entry *models.LogEntry) bool {
+	if m == nil {
+		return true
+	}
+	return m.Match(entry.Message) || m.Match(entry.Raw)
+}
+
+// literalMatcher does a case-sensitive substring search
+type literalMatcher struct {
+	pattern string
+}
+
+func (m *literalMatcher) Match(s string) bool {
+	return strings.Contains(s, m.pattern)
+}
+
+func (m *literalMatcher) String() string {
+	return m.pattern
+}
+
+// foldMatcher does a case-insensitive substring search
+type foldMatcher struct {
+	pattern string
+	lower   string
+}
+
+func (m *foldMatcher) Match(s string) bool {
+	return strings.Contains(strings.ToLower(s), m.lower)
+}
+
+func (m *foldMatcher) String() string {
+	return m.pattern
+}
+
+// regexMatcher backs both regex and glob modes
+type regexMatcher struct {
+	pattern string
+	re      *regexp.Regexp
+}
+
+func (m *regexMatcher) Match(s string) bool {
+	return m.re.MatchString(s)
+}
+
+func (m *regexMatcher) String() string {
+	return m.pattern
+}
+
+// globToRegex translates a glob into an anchored regular expression.
+// Globs with wildcards match the whole message, so "*timeout*" behaves
+// like a substring search and "timeout*" only matches at the start.
+func globToRegex(glob string, ignoreCase bool) string {
+	var b strings.Builder
+	b.WriteString("(?s")
+	if ignoreCase {
+		b.WriteString("i")
+	}
+	b.WriteString(")^")
+
+	runes := []rune(glob)
+	for i := 0; i < len(runes); i++ {
+		c := runes[i]
+		switch c {
+		case '*':
+			b.WriteString(".*")
+		case '?':
+			b.WriteString(".")
+		case '\\':
+			if i+1 < len(runes) {
+				i++
+				b.WriteString(regexp.QuoteMeta(string(runes[i])))
+			} else {
+				b.WriteString(`\\`)
+			}
+		case '[':
+			end := indexRune(runes[i+1:], ']')
+			if end < 0 {
+				b.WriteString(`\[`)
+				continue
+			}
+			b.WriteString("[" + globClass(runes[i+1:i+1+end]) + "]")
+			i += end + 1
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+
+	b.WriteString("$")
+	return b.String()
+}
+
+// globClass translates the contents of a glob character class, keeping
+// ranges and negation and escaping everything else
+func globClass(class []rune) string {
+	var b strings.Builder
+	for i := 0; i < len(class); i++ {
+		c := class[i]
+		switch {
+		case i == 0 && (c == '!' || c == '^'):
+			b.WriteString("^")
+		case c == '-' && i > 0 && i < len(class)-1:
+			b.WriteString("-")
+		case c == '\\' && i+1 < len(class):
+			i++
+			b.WriteString(quoteClassRune(class[i]))
+		default:
+			b.WriteString(quoteClassRune(c))
+		}
+	}
+	return b.String()
+}
+
+// quoteClassRune escapes a rune that is special inside a regex class
+func quoteClassRune(c rune) string {
+	if c == '-' {
+		return `\-`
+	}
+	return regexp.QuoteMeta(string(c))
+}
+
+// hasWildcard reports whether a glob has an unescaped *, ? or [
+func hasWildcard(glob string) bool {
+	for i := 0; i < len(glob); i++ {
+		switch glob[i] {
+		case '\\':
+			i++
+		case '*', '?', '[':
+			return true
+		}
+	}
+	return false
+}
+
+// unescapeGlob removes the backslash escapes of a glob without wildcards
+func unescapeGlob(glob string) string {
+	var b strings.Builder
+	for i := 0; i < len(glob); i++ {
+		if glob[i] == '\\' && i+1 < len(glob) {
+			i++
+		}
+		b.WriteByte(glob[i])
+	}
+	return b.String()
+}
+
+// indexRune returns the index of r in runes, or -1
+func indexRune(runes []rune, r rune) int {
+	for i, c := range runes {
+		if c == r {
+			return i
+		}
+	}
+	return -1
+}
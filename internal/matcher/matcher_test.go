@@ -0,0 +1,62 @@
+package matcher
+
+import "testing"
+
+func TestCompile(t *testing.T) {
+	tests := []struct {
+		name       string
+		pattern    string
+		mode       MatchMode
+		ignoreCase bool
+		input      string
+		want       bool
+	}{
+		{"literal substring", "timeout", Literal, false, "request timeout after 5s", true},
+		{"literal is case-sensitive", "Timeout", Literal, false, "request timeout", false},
+		{"literal ignoring case", "TIMEOUT", Literal, true, "request timeout", true},
+		{"literal has no metacharacters", "a.b", Literal, false, "axb", false},
+		{"regex", `timeout after \d+ms`, Regex, false, "timeout after 250ms", true},
+		{"regex ignoring case", `^error`, Regex, true, "ERROR: disk full", true},
+		{"glob with wildcards matches the whole message", "timeout*", Glob, false, "request timeout", false},
+		{"glob star", "*timeout*", Glob, false, "request timeout after 5s", true},
+		{"glob question mark", "user ?", Glob, false, "user 7", true},
+		{"glob without wildcards is a substring search", "timeout", Glob, false, "request timeout after 5s", true},
+		{"glob without wildcards ignoring case", "TIMEOUT", Glob, true, "request timeout", true},
+		{"glob escaped star is literal", `5\*`, Glob, false, "got 5* stars", true},
+		{"glob class", "code [45]0?", Glob, false, "code 503", true},
+		{"glob class range", "code [4-5]*", Glob, false, "code 404", true},
+		{"glob negated class", "code [!45]*", Glob, false, "code 404", false},
+		{"glob class metacharacters are literal", "x[.]y", Glob, false, "xzy", false},
+		{"glob class dot", "x[.]y", Glob, false, "x.y", true},
+		{"glob class backslash-d is not a digit class", `x[\d]y`, Glob, false, "x5y", false},
+		{"glob class letter d", `x[\d]y`, Glob, false, "xdy", true},
+		{"glob class escaped hyphen", `x[\-a]y`, Glob, false, "x-y", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := Compile(tt.pattern, tt.mode, tt.ignoreCase)
+			if err != nil {
+				t.Fatalf("Compile(%q): %v", tt.pattern, err)
+			}
+			if got := m.Match(tt.input); got != tt.want {
+				t.Errorf("Compile(%q, %s).Match(%q) = %v, want %v", tt.pattern, tt.mode, tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	tests := []struct {
+		pattern string
+		mode    MatchMode
+	}{
+		{"(unclosed", Regex},
+		{"[unclosed", Glob},
+	}
+	for _, tt := range tests {
+		if _, err := Compile(tt.pattern, tt.mode, false); err == nil {
+			t.Errorf("Compile(%q, %s) succeeded, want an error", tt.pattern, tt.mode)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package models
+
+// ContextWindow selects the entries of one source to report around
+// matches, like grep -B/-A. Entries that do not match wait in a ring of the
+// last Before entries and are released as context when a match follows;
+// the After entries following a match are released as context directly.
+// Every entry is released at most once, so overlapping windows merge.
+type ContextWindow struct {
+	before, after int
+
+	ring  []*LogEntry // Preceding entries, oldest at ring[head]
+	head  int
+	count int
+
+	remaining int  // Entries still to release after the last match
+	released  bool // Whether any entry was released yet
+	gap       bool // Whether entries were skipped since the last release
+}
+
+// NewContextWindow creates a window with before and after entries of context
+func NewContextWindow(before, after int) *ContextWindow {
+	return &ContextWindow{
+		before: before,
+		after:  after,
+		ring:   make([]*LogEntry, before),
+	}
+}
+
+// Add offers the next entry of the source and returns the entries to
+// report, oldest first
+func (c *ContextWindow) Add(entry *LogEntry, match bool) []*LogEntry {
+	if match {
+		result := make([]*LogEntry, 0, c.count+1)
+		for c.count > 0 {
+			result = append(result, c.release(c.pop(), true))
+		}
+		c.remaining = c.after
+		return append(result, c.release(entry, false))
+	}
+
+	if c.remaining > 0 {
+		c.remaining--
+		return []*LogEntry{c.release(entry, true)}
+	}
+
+	c.push(entry)
+	return nil
+}
+
+// push adds an entry to the ring, dropping the oldest when full
+func (c *ContextWindow) push(entry *LogEntry) {
+	if c.before == 0 {
+		c.gap = true
+		return
+	}
+	if c.count == c.before {
+		c.head = (c.head + 1) % c.before
+		c.count--
+		c.gap = true
+	}
+	c.ring[(c.head+c.count)%c.before] = entry
+	c.count++
+}
+
+// pop removes and returns the oldest entry of the ring
+func (c *ContextWindow) pop() *LogEntry {
+	entry := c.ring[c.head]
+	c.ring[c.head] = nil
+	c.head = (c.head + 1) % c.before
+	c.count--
+	return entry
+}
+
+// release marks an entry for reporting
+func (c *ContextWindow) release(entry *LogEntry, context bool) *LogEntry {
+	entry.Context = context
+	entry.GroupStart = !c.released || c.gap
+	c.released = true
+	c.gap = false
+	return entry
+}
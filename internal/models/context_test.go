@@ -0,0 +1,60 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestContextWindow(t *testing.T) {
+	// want lists the released entries by index, "." for a match and "-" for
+	// context, with "|" before each entry that starts a group
+	tests := []struct {
+		name          string
+		before, after int
+		entries       int
+		matches       []int
+		want          string
+	}{
+		{"before and after", 2, 1, 10, []int{5}, "|3- 4- 5. 6-"},
+		{"before at the start of the file", 3, 0, 10, []int{1}, "|0- 1."},
+		{"after at the end of the file", 0, 3, 10, []int{8}, "|8. 9-"},
+		{"before and after at both ends", 5, 5, 3, []int{1}, "|0- 1. 2-"},
+		{"overlapping windows merge", 1, 1, 10, []int{2, 4}, "|1- 2. 3- 4. 5-"},
+		{"match inside the after window", 0, 2, 10, []int{2, 3}, "|2. 3. 4- 5-"},
+		{"touching windows merge", 1, 1, 10, []int{2, 5}, "|1- 2. 3- 4- 5. 6-"},
+		{"separated windows", 1, 0, 10, []int{2, 6}, "|1- 2. |5- 6."},
+		{"adjacent matches", 1, 0, 10, []int{2, 3}, "|1- 2. 3."},
+		{"no context", 0, 0, 10, []int{2, 3, 5}, "|2. 3. |5."},
+		{"ring keeps only the last entries", 2, 0, 10, []int{9}, "|7- 8- 9."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			match := make(map[int]bool)
+			for _, i := range tt.matches {
+				match[i] = true
+			}
+
+			w := NewContextWindow(tt.before, tt.after)
+			var got []string
+			for i := 0; i < tt.entries; i++ {
+				for _, e := range w.Add(&LogEntry{Message: fmt.Sprint(i)}, match[i]) {
+					s := e.Message
+					if e.Context {
+						s += "-"
+					} else {
+						s += "."
+					}
+					if e.GroupStart {
+						s = "|" + s
+					}
+					got = append(got, s)
+				}
+			}
+			if strings.Join(got, " ") != tt.want {
+				t.Errorf("released %q, want %q", strings.Join(got, " "), tt.want)
+			}
+		})
+	}
+}
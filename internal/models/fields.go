@@ -0,0 +1,136 @@
+package models
+
+import (
+	"encoding/json"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Fields holds structured data attached to a log entry. Values are
+// string, float64 or bool; nested objects are flattened into dotted keys
+// ("http.status") and arrays into indexed keys ("tags.0").
+type Fields map[string]any
+
+// Set stores a value, normalizing numbers to float64 and flattening
+// nested maps and slices under key
+func (f Fields) Set(key string, value any) {
+	switch v := value.(type) {
+	case nil:
+		f[key] = nil
+	case map[string]any:
+		for k, inner := range v {
+			f.Set(key+"."+k, inner)
+		}
+	case []any:
+		for i, inner := range v {
+			f.Set(key+"."+strconv.Itoa(i), inner)
+		}
+	case json.Number:
+		if n, err := v.Float64(); err == nil && (math.Abs(n) <= 1<<53 || strings.ContainsAny(v.String(), ".eE")) {
+			f[key] = n
+		} else {
+			// Keep large integer IDs exact
+			f[key] = v.String()
+		}
+	case int:
+		f[key] = float64(v)
+	case int64:
+		f[key] = float64(v)
+	case float32:
+		f[key] = float64(v)
+	default:
+		f[key] = v
+	}
+}
+
+// SetInferred stores a string value, converting numbers and booleans.
+// Used for text formats where everything arrives as a string.
+func (f Fields) SetInferred(key, value string) {
+	if n, err := strconv.ParseFloat(value, 64); err == nil && !math.IsInf(n, 0) && !math.IsNaN(n) {
+		f[key] = n
+		return
+	}
+	if b, err := strconv.ParseBool(value); err == nil && (value == "true" || value == "false") {
+		f[key] = b
+		return
+	}
+	f[key] = value
+}
+
+// Get returns the raw value for a key
+func (f Fields) Get(key string) (any, bool) {
+	v, ok := f[key]
+	return v, ok
+}
+
+// GetString returns a value formatted as a string
+func (f Fields) GetString(key string) (string, bool) {
+	v, ok := f[key]
+	if !ok {
+		return "", false
+	}
+	return FormatFieldValue(v), true
+}
+
+// GetFloat returns a numeric value, parsing strings if necessary
+func (f Fields) GetFloat(key string) (float64, bool) {
+	switch v := f[key].(type) {
+	case float64:
+		return v, true
+	case bool:
+		if v {
+			return 1, true
+		}
+		return 0, true
+	case string:
+		n, err := strconv.ParseFloat(v, 64)
+		return n, err == nil
+	default:
+		return 0, false
+	}
+}
+
+// GetBool returns a boolean value, parsing strings if necessary
+func (f Fields) GetBool(key string) (bool, bool) {
+	switch v := f[key].(type) {
+	case bool:
+		return v, true
+	case string:
+		b, err := strconv.ParseBool(v)
+		return b, err == nil
+	default:
+		return false, false
+	}
+}
+
+// Keys returns the field names in sorted order
+func (f Fields) Keys() []string {
+	keys := make([]string, 0, len(f))
+	for k := range f {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// FormatFieldValue formats a field value for display
+func FormatFieldValue(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return ""
+		}
+		return string(b)
+	}
+}
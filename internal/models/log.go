@@ -2,6 +2,7 @@ package models
 
 import (
 	"fmt"
+	"strings"
 	"time"
 )
 
@@ -35,31 +36,82 @@ func (l LogLevel) String() string {
 	}
 }
 
+// AtLeast reports whether l is at or above min in severity. UNKNOWN has no
+// severity, so it only passes DEBUG, the minimum that admits every entry.
+func (l LogLevel) AtLeast(min LogLevel) bool {
+	if l == UNKNOWN {
+		return min == DEBUG
+	}
+	return l >= min
+}
+
 // ParseLogLevel converts a string to LogLevel
 func ParseLogLevel(s string) LogLevel {
 	switch s {
-	case "DEBUG":
+	case "DEBUG", "DBUG", "TRACE":
 		return DEBUG
-	case "INFO":
+	case "INFO", "NOTICE":
 		return INFO
 	case "WARN", "WARNING":
 		return WARN
-	case "ERROR":
+	case "ERROR", "ERR", "EROR":
 		return ERROR
-	case "FATAL":
+	case "FATAL", "CRIT", "CRITICAL", "PANIC", "DPANIC", "ALERT", "EMERG", "EMERGENCY":
 		return FATAL
 	default:
 		return UNKNOWN
 	}
 }
 
+// TimestampStatus records where an entry's timestamp came from (enum pattern)
+type TimestampStatus int
+
+const (
+	TimestampParsed  TimestampStatus = iota // Read from the line
+	TimestampMissing                        // The line had none; the time of parsing was used
+	TimestampInvalid                        // The line had one that could not be parsed
+)
+
+// String implements the Stringer interface for TimestampStatus
+func (t TimestampStatus) String() string {
+	switch t {
+	case TimestampParsed:
+		return "parsed"
+	case TimestampMissing:
+		return "missing"
+	case TimestampInvalid:
+		return "invalid"
+	default:
+		return "unknown"
+	}
+}
+
+// Position locates a line within its file
+type Position struct {
+	Line   int   // 1-based line number; 0 if unknown
+	Offset int64 // Byte offset of the line start (in the decompressed stream)
+}
+
 // LogEntry represents a parsed log line
 type LogEntry struct {
-	Timestamp time.Time
-	Level     LogLevel
-	Message   string
-	Source    string
-	Raw       string
+	Timestamp       time.Time
+	TimestampStatus TimestampStatus // Whether Timestamp came from the line
+	Level           LogLevel
+	LevelDefault    bool // Level was not in the line; the parser's default is used
+	Message         string
+	Source          string // Short name of the file, unique among the files analyzed
+	Raw             string
+	Fields          Fields // Structured data (JSON keys, key=value pairs); nil if none
+
+	// Provenance: the file and where in it the entry's first line starts
+	Path string
+	Position
+
+	// Set when context lines are requested: Context marks an entry shown
+	// only because it is near a match, GroupStart the first entry of each
+	// run of matches and context
+	Context    bool
+	GroupStart bool
 }
 
 // String implements the Stringer interface for LogEntry
@@ -72,29 +124,29 @@ func (e LogEntry) String() string {
 	)
 }
 
+// Location returns where the entry came from as "path:line", the form
+// accepted by the show command
+func (e *LogEntry) Location() string {
+	path := e.Path
+	if path == "" {
+		path = e.Source
+	}
+	if e.Line <= 0 {
+		return path
+	}
+	return fmt.Sprintf("%s:%d", path, e.Line)
+}
+
 // MatchesLevel checks if the entry matches the given level
 func (e *LogEntry) MatchesLevel(level LogLevel) bool {
 	return e.Level == level
 }
 
-// MatchesPattern checks if the entry contains the pattern
+// MatchesPattern checks if the entry contains the pattern as a substring.
+// Use the matcher package for regex, glob or case-insensitive matching.
 func (e *LogEntry) MatchesPattern(pattern string) bool {
 	if pattern == "" {
 		return true
 	}
-	// Simple substring match (you can enhance with regex)
-	return contains(e.Message, pattern) || contains(e.Raw, pattern)
-}
-
-func contains(s, substr string) bool {
-	return len(s) >= len(substr) && (s == substr || findSubstring(s, substr))
-}
-
-func findSubstring(s, substr string) bool {
-	for i := 0; i <= len(s)-len(substr); i++ {
-		if s[i:i+len(substr)] == substr {
-			return true
-		}
-	}
-	return false
+	return strings.Contains(e.Message, pattern) || strings.Contains(e.Raw, pattern)
 }
@@ -0,0 +1,65 @@
+package models
+
+// ParseQuality counts how well the lines of one source parsed
+type ParseQuality struct {
+	Records          int // Records handed to a parser
+	Failed           int // Records every parser rejected
+	Mismatched       int // Records the source's parser rejected that only the plain-text fallback took
+	TimestampMissing int // Entries whose line had no timestamp
+	TimestampInvalid int // Entries whose timestamp could not be parsed
+	UnknownLevel     int // Entries without a recognized level
+	Degraded         int // Entries with any of the problems above
+
+	// Entries of formats without a mandatory level (plain text, syslog
+	// without PRI) that named none and got the default. Not a problem.
+	LevelDefault int
+}
+
+// Observe records the quality of one parsed entry
+func (q *ParseQuality) Observe(entry *LogEntry) {
+	degraded := false
+	switch entry.TimestampStatus {
+	case TimestampMissing:
+		q.TimestampMissing++
+		degraded = true
+	case TimestampInvalid:
+		q.TimestampInvalid++
+		degraded = true
+	}
+	if entry.Level == UNKNOWN {
+		q.UnknownLevel++
+		degraded = true
+	}
+	if entry.LevelDefault {
+		q.LevelDefault++
+	}
+	if degraded {
+		q.Degraded++
+	}
+}
+
+// Add merges the counts of other into q
+func (q *ParseQuality) Add(other ParseQuality) {
+	q.Records += other.Records
+	q.Failed += other.Failed
+	q.Mismatched += other.Mismatched
+	q.TimestampMissing += other.TimestampMissing
+	q.TimestampInvalid += other.TimestampInvalid
+	q.UnknownLevel += other.UnknownLevel
+	q.Degraded += other.Degraded
+	q.LevelDefault += other.LevelDefault
+}
+
+// BadRatio returns the share of records that failed to parse, fell back to
+// plain text or parsed with a problem, from 0 to 1
+func (q ParseQuality) BadRatio() float64 {
+	if q.Records == 0 {
+		return 0
+	}
+	return float64(q.Failed+q.Mismatched+q.Degraded) / float64(q.Records)
+}
+
+// Clean reports whether every record parsed without problems
+func (q ParseQuality) Clean() bool {
+	return q.Failed == 0 && q.Mismatched == 0 && q.Degraded == 0
+}
@@ -2,6 +2,8 @@ package models
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 )
@@ -13,6 +15,8 @@ type Statistics struct {
 	LevelCounts    map[LogLevel]int
 	PatternCounts  map[string]int
 	SourceCounts   map[string]int
+	ParserCounts   map[string]map[string]int // Entries parsed per source, by parser name
+	Quality        map[string]*ParseQuality  // Parse failures and problems per source
 	FirstTimestamp time.Time
 	LastTimestamp  time.Time
 	ProcessingTime time.Duration
@@ -26,6 +30,8 @@ func NewStatistics() *Statistics {
 		LevelCounts:   make(map[LogLevel]int),
 		PatternCounts: make(map[string]int),
 		SourceCounts:  make(map[string]int),
+		ParserCounts:  make(map[string]map[string]int),
+		Quality:       make(map[string]*ParseQuality),
 	}
 }
 
@@ -54,6 +60,127 @@ func (s *Statistics) IncrementPattern(pattern string) {
 	s.PatternCounts[pattern]++
 }
 
+// SetPatternCounts replaces all pattern counts, e.g. with mined templates (thread-safe)
+func (s *Statistics) SetPatternCounts(counts map[string]int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.PatternCounts = counts
+}
+
+// AddParserCounts records how many lines of a source each parser parsed (thread-safe)
+func (s *Statistics) AddParserCounts(source string, counts map[string]int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.ParserCounts[source] == nil {
+		s.ParserCounts[source] = make(map[string]int)
+	}
+	for name, count := range counts {
+		s.ParserCounts[source][name] += count
+	}
+}
+
+// AddParseQuality merges the parse quality counts of a source (thread-safe)
+func (s *Statistics) AddParseQuality(source string, quality ParseQuality) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.Quality[source] == nil {
+		s.Quality[source] = &ParseQuality{}
+	}
+	s.Quality[source].Add(quality)
+}
+
+// ParseQualityBySource returns a copy of the parse quality counts per source (thread-safe)
+func (s *Statistics) ParseQualityBySource() map[string]ParseQuality {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := make(map[string]ParseQuality, len(s.Quality))
+	for source, quality := range s.Quality {
+		result[source] = *quality
+	}
+	return result
+}
+
+// TotalParseQuality returns the parse quality counts of all sources (thread-safe)
+func (s *Statistics) TotalParseQuality() ParseQuality {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var total ParseQuality
+	for _, quality := range s.Quality {
+		total.Add(*quality)
+	}
+	return total
+}
+
+// SourceParsers describes the parsers used for each source, e.g. "JSON" or
+// "JSON 98%, PlainText 2%" when a source mixes formats (thread-safe)
+func (s *Statistics) SourceParsers() map[string]string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := make(map[string]string, len(s.ParserCounts))
+	for source, counts := range s.ParserCounts {
+		total := 0
+		names := make([]string, 0, len(counts))
+		for name, count := range counts {
+			total += count
+			names = append(names, name)
+		}
+		if total == 0 {
+			continue
+		}
+		sort.Slice(names, func(i, j int) bool {
+			if counts[names[i]] != counts[names[j]] {
+				return counts[names[i]] > counts[names[j]]
+			}
+			return names[i] < names[j]
+		})
+
+		if len(names) == 1 {
+			result[source] = names[0]
+			continue
+		}
+		parts := make([]string, len(names))
+		for i, name := range names {
+			parts[i] = fmt.Sprintf("%s %.0f%%", name, float64(counts[name])*100/float64(total))
+		}
+		result[source] = strings.Join(parts, ", ")
+	}
+	return result
+}
+
+// PatternCount pairs a pattern with its number of occurrences
+type PatternCount struct {
+	Pattern string
+	Count   int
+}
+
+// TopPatterns returns the n most frequent patterns, most frequent first (thread-safe)
+func (s *Statistics) TopPatterns(n int) []PatternCount {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	patterns := make([]PatternCount, 0, len(s.PatternCounts))
+	for pattern, count := range s.PatternCounts {
+		patterns = append(patterns, PatternCount{pattern, count})
+	}
+
+	sort.Slice(patterns, func(i, j int) bool {
+		if patterns[i].Count != patterns[j].Count {
+			return patterns[i].Count > patterns[j].Count
+		}
+		return patterns[i].Pattern < patterns[j].Pattern
+	})
+
+	if n >= 0 && n < len(patterns) {
+		patterns = patterns[:n]
+	}
+	return patterns
+}
+
 // AddFile updates file processing stats (thread-safe)
 func (s *Statistics) AddFile(bytesRead int64) {
 	s.mu.Lock()
@@ -76,6 +203,34 @@ func (s *Statistics) GetLevelCount(level LogLevel) int {
 	return s.LevelCounts[level]
 }
 
+// EntriesPerSecond returns the processing rate in entries per second (thread-safe)
+func (s *Statistics) EntriesPerSecond() float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.entriesPerSecond()
+}
+
+// Throughput returns the processing rate in MB per second (thread-safe)
+func (s *Statistics) Throughput() float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.throughput()
+}
+
+func (s *Statistics) entriesPerSecond() float64 {
+	if s.ProcessingTime <= 0 {
+		return 0
+	}
+	return float64(s.TotalEntries) / s.ProcessingTime.Seconds()
+}
+
+func (s *Statistics) throughput() float64 {
+	if s.ProcessingTime <= 0 {
+		return 0
+	}
+	return float64(s.BytesProcessed) / (1024 * 1024) / s.ProcessingTime.Seconds()
+}
+
 // Summary returns a formatted summary string
 func (s *Statistics) Summary() string {
 	s.mu.Lock()
@@ -93,6 +248,7 @@ Total Entries:      %d
 Bytes Processed:    %.2f MB
 Processing Time:    %s
 Entries/sec:        %.0f
+Throughput:         %.2f MB/s
 
 📈 Log Levels
 ────────────────────────────────────────────────
@@ -101,6 +257,7 @@ INFO:    %6d
 WARN:    %6d
 ERROR:   %6d
 FATAL:   %6d
+UNKNOWN: %6d
 
 ⏰ Time Range
 ────────────────────────────────────────────────
@@ -112,12 +269,14 @@ Duration:           %s
 		s.TotalEntries,
 		float64(s.BytesProcessed)/(1024*1024),
 		s.ProcessingTime.Round(time.Millisecond),
-		float64(s.TotalEntries)/s.ProcessingTime.Seconds(),
+		s.entriesPerSecond(),
+		s.throughput(),
 		s.LevelCounts[DEBUG],
 		s.LevelCounts[INFO],
 		s.LevelCounts[WARN],
 		s.LevelCounts[ERROR],
 		s.LevelCounts[FATAL],
+		s.LevelCounts[UNKNOWN],
 		s.FirstTimestamp.Format("2006-01-02 15:04:05"),
 		s.LastTimestamp.Format("2006-01-02 15:04:05"),
 		s.LastTimestamp.Sub(s.FirstTimestamp).Round(time.Second),
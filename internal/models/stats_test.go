@@ -0,0 +1,31 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"testing"
+)
+
+func TestSummaryLevelRowsAddUp(t *testing.T) {
+	stats := NewStatistics()
+	for i, level := range []LogLevel{DEBUG, INFO, INFO, WARN, ERROR, FATAL, UNKNOWN, UNKNOWN} {
+		stats.AddEntry(&LogEntry{Level: level, Message: fmt.Sprint(i)})
+	}
+
+	rows := regexp.MustCompile(`(?m)^(DEBUG|INFO|WARN|ERROR|FATAL|UNKNOWN):\s+(\d+)$`).FindAllStringSubmatch(stats.Summary(), -1)
+	if len(rows) != 6 {
+		t.Fatalf("found %d level rows, want 6:\n%s", len(rows), stats.Summary())
+	}
+	sum := 0
+	for _, row := range rows {
+		count, _ := strconv.Atoi(row[2])
+		if want := stats.GetLevelCount(ParseLogLevel(row[1])); count != want {
+			t.Errorf("%s row = %d, want %d", row[1], count, want)
+		}
+		sum += count
+	}
+	if sum != stats.TotalEntries {
+		t.Errorf("level rows add up to %d, want Total Entries %d", sum, stats.TotalEntries)
+	}
+}
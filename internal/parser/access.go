@@ -0,0 +1,272 @@
+package parser
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aadithyaa9/loganalyzer/internal/models"
+)
+
+func init() {
+	// Combined is the stricter layout, so it outranks common
+	Register(Registration{
+		Name:       "combined",
+		Confidence: 0.95,
+		New: func(opts Options) (LogParser, error) {
+			return &CombinedLogParser{}, nil
+		},
+	})
+	Register(Registration{
+		Name:       "common",
+		Confidence: 0.9,
+		New: func(opts Options) (LogParser, error) {
+			return &CommonLogParser{}, nil
+		},
+	})
+}
+
+// accessTimeFormat is the Common Log Format timestamp, e.g. 10/Oct/2000:13:55:36 -0700
+const accessTimeFormat = "02/Jan/2006:15:04:05 -0700"
+
+// Access log line layouts
+var (
+	// commonLogLine matches: host ident authuser [date] "request" status bytes
+	commonLogLine = regexp.MustCompile(`^(\S+) (\S+) (\S+) \[([^\]]+)\] "((?:[^"\\]|\\.)*)" (\d{3}) (\d+|-)`)
+
+	// combinedLogLine adds "referer" "user-agent" and captures anything after
+	// them, where nginx configs commonly append $request_time and
+	// $upstream_response_time
+	combinedLogLine = regexp.MustCompile(`^(\S+) (\S+) (\S+) \[([^\]]+)\] "((?:[^"\\]|\\.)*)" (\d{3}) (\d+|-) "((?:[^"\\]|\\.)*)" "((?:[^"\\]|\\.)*)"(.*)$`)
+)
+
+// Keys that nginx log_format examples use for timings in key=value form
+var (
+	requestTimeKeys  = []string{"request_time", "rt"}
+	upstreamTimeKeys = []string{"upstream_response_time", "urt", "upstream_time"}
+
+	// timingListSeparator matches the separators inside multi-upstream timings
+	timingListSeparator = regexp.MustCompile(`\s*([,:])\s*`)
+)
+
+// CommonLogParser parses Apache/nginx Common Log Format access logs
+type CommonLogParser struct{}
+
+// Parse parses a Common Log Format line
+// Expected format:
+// 127.0.0.1 - frank [10/Oct/2000:13:55:36 -0700] "GET /apache_pb.gif HTTP/1.0" 200 2326
+func (p *CommonLogParser) Parse(line string, source string) (*models.LogEntry, error) {
+	if strings.TrimSpace(line) == "" {
+		return nil, ErrEmptyLine
+	}
+
+	m := commonLogLine.FindStringSubmatch(line)
+	if m == nil {
+		return nil, fmt.Errorf("%w: not a common log format line", ErrInvalidFormat)
+	}
+	return newAccessEntry(m, line, source)
+}
+
+// CanParse checks if a line is in Common Log Format
+func (p *CommonLogParser) CanParse(line string) bool {
+	return strings.Contains(line, `] "`) && commonLogLine.MatchString(line)
+}
+
+// Name returns the parser name
+func (p *CommonLogParser) Name() string {
+	return "CommonLog"
+}
+
+// CombinedLogParser parses Combined Log Format access logs, including nginx
+// variants that append request and upstream response times
+type CombinedLogParser struct{}
+
+// Parse parses a Combined Log Format line
+// Expected formats:
+// 127.0.0.1 - - [10/Oct/2000:13:55:36 -0700] "GET / HTTP/1.1" 200 612 "-" "curl/8.0"
+// 127.0.0.1 - - [10/Oct/2000:13:55:36 -0700] "GET / HTTP/1.1" 200 612 "-" "curl/8.0" 0.005 0.004
+// 127.0.0.1 - - [10/Oct/2000:13:55:36 -0700] "GET / HTTP/1.1" 200 612 "-" "curl/8.0" rt=0.005 urt="0.004"
+func (p *CombinedLogParser) Parse(line string, source string) (*models.LogEntry, error) {
+	if strings.TrimSpace(line) == "" {
+		return nil, ErrEmptyLine
+	}
+
+	m := combinedLogLine.FindStringSubmatch(line)
+	if m == nil {
+		return nil, fmt.Errorf("%w: not a combined log format line", ErrInvalidFormat)
+	}
+
+	entry, err := newAccessEntry(m[:8], line, source)
+	if err != nil {
+		return nil, err
+	}
+
+	setUnlessDash(entry.Fields, "referer", unescapeAccessField(m[8]))
+	setUnlessDash(entry.Fields, "user_agent", unescapeAccessField(m[9]))
+	parseAccessTimings(entry.Fields, m[10])
+
+	return entry, nil
+}
+
+// CanParse checks if a line is in Combined Log Format
+func (p *CombinedLogParser) CanParse(line string) bool {
+	return strings.Contains(line, `] "`) && combinedLogLine.MatchString(line)
+}
+
+// Name returns the parser name
+func (p *CombinedLogParser) Name() string {
+	return "CombinedLog"
+}
+
+// newAccessEntry builds an entry from the Common Log Format submatches
+func newAccessEntry(m []string, line, source string) (*models.LogEntry, error) {
+	timestamp, err := time.Parse(accessTimeFormat, m[4])
+	if err != nil {
+		return nil, fmt.Errorf("%w: bad access log timestamp %q", ErrInvalidFormat, m[4])
+	}
+
+	status, _ := strconv.Atoi(m[6])
+	request := unescapeAccessField(m[5])
+
+	fields := models.Fields{
+		"client_ip": m[1],
+		"status":    float64(status),
+	}
+	setUnlessDash(fields, "ident", m[2])
+	setUnlessDash(fields, "user", m[3])
+
+	// "GET /path HTTP/1.1"; malformed requests keep the raw request as path
+	if parts := strings.Fields(request); len(parts) >= 2 && len(parts) <= 3 {
+		fields["method"] = parts[0]
+		fields["path"] = parts[1]
+		if len(parts) == 3 {
+			fields["protocol"] = parts[2]
+		}
+	} else if request != "" && request != "-" {
+		fields["path"] = request
+	}
+
+	if m[7] != "-" {
+		fields.SetInferred("bytes", m[7])
+	} else {
+		fields["bytes"] = float64(0)
+	}
+
+	return &models.LogEntry{
+		Timestamp: timestamp,
+		Level:     levelFromStatus(status),
+		Message:   fmt.Sprintf("%s %d", request, status),
+		Source:    source,
+		Raw:       line,
+		Fields:    fields,
+	}, nil
+}
+
+// levelFromStatus maps an HTTP status to a level: 5xx ERROR, 4xx WARN, else INFO
+func levelFromStatus(status int) models.LogLevel {
+	switch {
+	case status >= 500:
+		return models.ERROR
+	case status >= 400:
+		return models.WARN
+	default:
+		return models.INFO
+	}
+}
+
+// parseAccessTimings reads the nginx timings appended after the user agent.
+// Bare numbers are taken as $request_time then $upstream_response_time;
+// key=value pairs are recognized by name. Both land in seconds as
+// "latency" and "upstream_latency".
+func parseAccessTimings(fields models.Fields, rest string) {
+	rest = strings.TrimSpace(rest)
+	if rest == "" {
+		return
+	}
+
+	kv := extractKeyValues(rest)
+	for key, value := range kv {
+		fields[key] = value
+	}
+	if latency, ok := takeSeconds(fields, requestTimeKeys); ok {
+		fields["latency"] = latency
+	}
+	if latency, ok := takeSeconds(fields, upstreamTimeKeys); ok {
+		fields["upstream_latency"] = latency
+	}
+
+	// Positional timings, e.g. `... "curl/8.0" 0.005 0.004`
+	// Multi-upstream lists ("0.010, 0.020") are joined into one token first.
+	var positional []string
+	unlabelled := timingListSeparator.ReplaceAllString(keyValuePair.ReplaceAllString(rest, " "), "$1")
+	for _, token := range strings.Fields(unlabelled) {
+		positional = append(positional, strings.Trim(token, `"`))
+	}
+	if _, ok := fields["latency"]; !ok && len(positional) > 0 {
+		if latency, ok := sumSeconds(positional[0]); ok {
+			fields["latency"] = latency
+		}
+	}
+	if _, ok := fields["upstream_latency"]; !ok && len(positional) > 1 {
+		if latency, ok := sumSeconds(positional[1]); ok {
+			fields["upstream_latency"] = latency
+		}
+	}
+}
+
+// takeSeconds removes the given keys and returns the first parsable timing
+func takeSeconds(fields models.Fields, keys []string) (float64, bool) {
+	var (
+		result float64
+		found  bool
+	)
+	for _, key := range keys {
+		value, ok := fields[key]
+		if !ok {
+			continue
+		}
+		delete(fields, key)
+		if seconds, ok := sumSeconds(models.FormatFieldValue(value)); ok && !found {
+			result, found = seconds, true
+		}
+	}
+	return result, found
+}
+
+// sumSeconds parses an nginx timing. Requests retried across upstreams log
+// "0.010, 0.020" (or "0.010 : 0.020" across upstream groups), so the
+// attempts are summed; "-" means no upstream was contacted.
+func sumSeconds(value string) (float64, bool) {
+	var (
+		total float64
+		found bool
+	)
+	for _, part := range strings.FieldsFunc(value, func(r rune) bool {
+		return r == ',' || r == ':' || r == ' '
+	}) {
+		seconds, err := strconv.ParseFloat(part, 64)
+		if err != nil {
+			continue
+		}
+		total += seconds
+		found = true
+	}
+	return total, found
+}
+
+// setUnlessDash sets a field unless the log used "-" for an absent value
+func setUnlessDash(fields models.Fields, key, value string) {
+	if value != "" && value != "-" {
+		fields[key] = value
+	}
+}
+
+// unescapeAccessField undoes the \" and \\ escaping used inside quoted fields
+func unescapeAccessField(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	return strings.NewReplacer(`\"`, `"`, `\\`, `\`).Replace(s)
+}
@@ -0,0 +1,138 @@
+package parser
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aadithyaa9/loganalyzer/internal/models"
+)
+
+func TestAccessLogParsers(t *testing.T) {
+	tests := []struct {
+		name    string
+		parser  LogParser
+		line    string
+		level   models.LogLevel
+		message string
+		fields  map[string]any
+		absent  []string
+		wantErr bool
+	}{
+		{
+			name:    "common",
+			parser:  &CommonLogParser{},
+			line:    `127.0.0.1 - frank [10/Oct/2000:13:55:36 -0700] "GET /apache_pb.gif HTTP/1.0" 200 2326`,
+			level:   models.INFO,
+			message: "GET /apache_pb.gif HTTP/1.0 200",
+			fields: map[string]any{
+				"client_ip": "127.0.0.1", "user": "frank", "method": "GET",
+				"path": "/apache_pb.gif", "protocol": "HTTP/1.0", "status": 200.0, "bytes": 2326.0,
+			},
+			absent: []string{"ident"},
+		},
+		{
+			name:    "common without a body",
+			parser:  &CommonLogParser{},
+			line:    `10.0.0.1 - - [10/Oct/2000:13:55:36 +0000] "HEAD / HTTP/1.1" 404 -`,
+			level:   models.WARN,
+			message: "HEAD / HTTP/1.1 404",
+			fields:  map[string]any{"status": 404.0, "bytes": 0.0},
+			absent:  []string{"user"},
+		},
+		{
+			name:    "malformed request keeps the raw request as path",
+			parser:  &CommonLogParser{},
+			line:    `10.0.0.1 - - [10/Oct/2000:13:55:36 +0000] "\x16\x03\x01" 400 0`,
+			level:   models.WARN,
+			message: `\x16\x03\x01 400`,
+			fields:  map[string]any{"path": `\x16\x03\x01`},
+			absent:  []string{"method"},
+		},
+		{
+			name:    "combined",
+			parser:  &CombinedLogParser{},
+			line:    `127.0.0.1 - - [10/Oct/2000:13:55:36 -0700] "POST /api HTTP/1.1" 503 12 "https://example.com/" "curl/8.0"`,
+			level:   models.ERROR,
+			message: "POST /api HTTP/1.1 503",
+			fields:  map[string]any{"referer": "https://example.com/", "user_agent": "curl/8.0", "status": 503.0},
+			absent:  []string{"latency"},
+		},
+		{
+			name:   "combined with escaped quotes",
+			parser: &CombinedLogParser{},
+			line:   `127.0.0.1 - - [10/Oct/2000:13:55:36 -0700] "GET / HTTP/1.1" 200 612 "-" "agent \"quoted\""`,
+			level:  models.INFO,
+			fields: map[string]any{"user_agent": `agent "quoted"`},
+			absent: []string{"referer"},
+		},
+		{
+			name:   "nginx positional timings",
+			parser: &CombinedLogParser{},
+			line:   `127.0.0.1 - - [10/Oct/2000:13:55:36 -0700] "GET / HTTP/1.1" 200 612 "-" "curl/8.0" 0.005 0.004`,
+			level:  models.INFO,
+			fields: map[string]any{"latency": 0.005, "upstream_latency": 0.004},
+		},
+		{
+			name:   "nginx key=value timings across upstreams",
+			parser: &CombinedLogParser{},
+			line:   `127.0.0.1 - - [10/Oct/2000:13:55:36 -0700] "GET / HTTP/1.1" 502 0 "-" "curl/8.0" rt=0.5 urt="0.25, 0.25"`,
+			level:  models.ERROR,
+			fields: map[string]any{"latency": 0.5, "upstream_latency": 0.5},
+			absent: []string{"rt", "urt"},
+		},
+		{
+			name:    "combined rejects a common line",
+			parser:  &CombinedLogParser{},
+			line:    `127.0.0.1 - frank [10/Oct/2000:13:55:36 -0700] "GET / HTTP/1.0" 200 2326`,
+			wantErr: true,
+		},
+		{
+			name:    "bad timestamp",
+			parser:  &CommonLogParser{},
+			line:    `127.0.0.1 - - [yesterday] "GET / HTTP/1.0" 200 1`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry, err := tt.parser.Parse(tt.line, "access.log")
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Parse(%q) succeeded, want an error", tt.line)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.line, err)
+			}
+			if entry.Level != tt.level {
+				t.Errorf("level = %s, want %s", entry.Level, tt.level)
+			}
+			if tt.message != "" && entry.Message != tt.message {
+				t.Errorf("message = %q, want %q", entry.Message, tt.message)
+			}
+			for key, want := range tt.fields {
+				if got, ok := entry.Fields.Get(key); !ok || got != want {
+					t.Errorf("fields[%q] = %v, want %v", key, got, want)
+				}
+			}
+			for _, key := range tt.absent {
+				if got, ok := entry.Fields.Get(key); ok {
+					t.Errorf("fields[%q] = %v, want it absent", key, got)
+				}
+			}
+		})
+	}
+}
+
+func TestAccessLogTimestamp(t *testing.T) {
+	entry, err := (&CommonLogParser{}).Parse(`::1 - - [10/Oct/2000:13:55:36 -0700] "GET / HTTP/1.1" 200 1`, "access.log")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := time.Date(2000, 10, 10, 20, 55, 36, 0, time.UTC)
+	if !entry.Timestamp.Equal(want) {
+		t.Errorf("timestamp = %s, want %s", entry.Timestamp, want)
+	}
+}
@@ -0,0 +1,247 @@
+package parser
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/aadithyaa9/loganalyzer/internal/models"
+)
+
+// ContainerFormat is the envelope a container runtime wraps log lines in
+type ContainerFormat int
+
+const (
+	ContainerNone   ContainerFormat = iota // Plain application lines
+	ContainerCRI                           // containerd / CRI-O: "<time> <stream> <P|F> <line>"
+	ContainerDocker                        // docker json-file: {"log":...,"stream":...,"time":...}
+)
+
+// String returns the format name
+func (f ContainerFormat) String() string {
+	switch f {
+	case ContainerCRI:
+		return "CRI"
+	case ContainerDocker:
+		return "Docker"
+	default:
+		return "none"
+	}
+}
+
+// criLine matches a CRI log line; the tag is P (partial) or F (full),
+// optionally followed by further ':'-separated tags
+var criLine = regexp.MustCompile(`^(\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(?:\.\d+)?(?:Z|[+-]\d{2}:\d{2})) (stdout|stderr) ([PF])(?::\S*)? ?(.*)$`)
+
+// Path layouts that identify the pod and container of a log file
+var (
+	// /var/log/pods/<namespace>_<pod>_<uid>/<container>/0.log
+	podLogPath = regexp.MustCompile(`(?:^|/)pods/([^/_]+)_([^/_]+)_([0-9a-f-]+)/([^/]+)/\d+\.log`)
+
+	// /var/log/containers/<pod>_<namespace>_<container>-<id>.log
+	containerLogPath = regexp.MustCompile(`(?:^|/)containers/([^/_]+)_([^/_]+)_(.+)-([0-9a-f]{64})\.log`)
+
+	// /var/lib/docker/containers/<id>/<id>-json.log
+	dockerLogPath = regexp.MustCompile(`(?:^|/)containers/([0-9a-f]{64})/[0-9a-f]{64}-json\.log`)
+)
+
+// ContainerEnvelope is what the runtime recorded about one application line
+type ContainerEnvelope struct {
+	Format    ContainerFormat
+	Timestamp time.Time
+	Stream    string // "stdout" or "stderr"
+}
+
+// dockerLine is one record of the docker json-file log driver
+type dockerLine struct {
+	Log    string `json:"log"`
+	Stream string `json:"stream"`
+	Time   string `json:"time"`
+}
+
+// ContainerUnwrapper strips container runtime envelopes from raw lines and
+// reassembles lines the runtime split into partial records. The format is
+// detected from the first line; files without an envelope pass through.
+type ContainerUnwrapper struct {
+	format   ContainerFormat
+	detected bool
+
+	// Partial lines waiting for their final record, per stream
+	partial map[string]*partialLine
+	order   []string // Streams with partial lines, oldest first
+}
+
+// partialLine is an application line split across several records
+type partialLine struct {
+	envelope ContainerEnvelope
+	position models.Position // Of the first record
+	text     strings.Builder
+}
+
+// NewContainerUnwrapper creates an unwrapper
+func NewContainerUnwrapper() *ContainerUnwrapper {
+	return &ContainerUnwrapper{partial: make(map[string]*partialLine)}
+}
+
+// Format returns the detected envelope format
+func (u *ContainerUnwrapper) Format() ContainerFormat {
+	return u.format
+}
+
+// Unwrap feeds one raw line found at pos and returns the application line
+// inside it with its envelope. It returns false while a partial line is
+// buffered. Lines without an envelope are returned unchanged with a nil
+// envelope.
+func (u *ContainerUnwrapper) Unwrap(line string, pos models.Position) (Record, bool) {
+	if !u.detected {
+		if strings.TrimSpace(line) == "" {
+			return Record{Text: line, Position: pos}, true
+		}
+		u.format = DetectContainerFormat(line)
+		u.detected = true
+	}
+
+	var (
+		envelope ContainerEnvelope
+		text     string
+		partial  bool
+		ok       bool
+	)
+	switch u.format {
+	case ContainerCRI:
+		envelope, text, partial, ok = parseCRILine(line)
+	case ContainerDocker:
+		envelope, text, partial, ok = parseDockerLine(line)
+	}
+	if !ok {
+		return Record{Text: line, Position: pos}, true
+	}
+
+	buffered, pending := u.partial[envelope.Stream]
+	if partial {
+		if !pending {
+			// The line takes the time and position of its first record
+			buffered = &partialLine{envelope: envelope, position: pos}
+			u.partial[envelope.Stream] = buffered
+			u.order = append(u.order, envelope.Stream)
+		}
+		buffered.text.WriteString(text)
+		return Record{}, false
+	}
+	if pending {
+		buffered.text.WriteString(text)
+		u.drop(envelope.Stream)
+		return buffered.record(), true
+	}
+	return Record{Text: text, Envelope: &envelope, Position: pos}, true
+}
+
+// Flush returns a partial line whose final record never arrived, oldest
+// stream first; call it until it returns false
+func (u *ContainerUnwrapper) Flush() (Record, bool) {
+	if len(u.order) == 0 {
+		return Record{}, false
+	}
+	stream := u.order[0]
+	buffered := u.partial[stream]
+	u.drop(stream)
+	return buffered.record(), true
+}
+
+// record returns the reassembled line
+func (p *partialLine) record() Record {
+	return Record{Text: p.text.String(), Envelope: &p.envelope, Position: p.position}
+}
+
+// drop forgets the partial line of a stream
+func (u *ContainerUnwrapper) drop(stream string) {
+	delete(u.partial, stream)
+	for i, s := range u.order {
+		if s == stream {
+			u.order = append(u.order[:i], u.order[i+1:]...)
+			break
+		}
+	}
+}
+
+// Apply records the envelope on an entry parsed from the inner line. The
+// runtime timestamp replaces the entry's when the application line had
+// none or an unreadable one.
+func (e *ContainerEnvelope) Apply(entry *models.LogEntry) {
+	if e == nil {
+		return
+	}
+	if entry.Fields == nil {
+		entry.Fields = make(models.Fields)
+	}
+	entry.Fields["stream"] = e.Stream
+	if !e.Timestamp.IsZero() && entry.TimestampStatus != models.TimestampParsed {
+		entry.Timestamp = e.Timestamp
+		entry.TimestampStatus = models.TimestampParsed
+	}
+}
+
+// ContainerPathFields derives the pod, namespace and container of a log
+// file from the kubelet and docker directory layouts. It returns nil for
+// other paths.
+func ContainerPathFields(path string) models.Fields {
+	path = filepath.ToSlash(path)
+	if m := podLogPath.FindStringSubmatch(path); m != nil {
+		return models.Fields{"namespace": m[1], "pod": m[2], "pod_uid": m[3], "container": m[4]}
+	}
+	if m := containerLogPath.FindStringSubmatch(path); m != nil {
+		return models.Fields{"pod": m[1], "namespace": m[2], "container": m[3], "container_id": m[4]}
+	}
+	if m := dockerLogPath.FindStringSubmatch(path); m != nil {
+		return models.Fields{"container_id": m[1]}
+	}
+	return nil
+}
+
+// DetectContainerFormat identifies the envelope of a line
+func DetectContainerFormat(line string) ContainerFormat {
+	if _, _, _, ok := parseCRILine(line); ok {
+		return ContainerCRI
+	}
+	if _, _, _, ok := parseDockerLine(line); ok {
+		return ContainerDocker
+	}
+	return ContainerNone
+}
+
+// parseCRILine splits a CRI line into its envelope and application text
+func parseCRILine(line string) (ContainerEnvelope, string, bool, bool) {
+	m := criLine.FindStringSubmatch(line)
+	if m == nil {
+		return ContainerEnvelope{}, "", false, false
+	}
+	timestamp, err := time.Parse(time.RFC3339Nano, m[1])
+	if err != nil {
+		return ContainerEnvelope{}, "", false, false
+	}
+	envelope := ContainerEnvelope{Format: ContainerCRI, Timestamp: timestamp, Stream: m[2]}
+	return envelope, m[4], m[3] == "P", true
+}
+
+// parseDockerLine decodes a docker json-file record. The driver keeps the
+// newline in "log"; records without one are partial.
+func parseDockerLine(line string) (ContainerEnvelope, string, bool, bool) {
+	trimmed := strings.TrimSpace(line)
+	if !strings.HasPrefix(trimmed, `{"log":`) {
+		return ContainerEnvelope{}, "", false, false
+	}
+	var record dockerLine
+	if err := json.Unmarshal([]byte(trimmed), &record); err != nil || record.Stream == "" {
+		return ContainerEnvelope{}, "", false, false
+	}
+
+	envelope := ContainerEnvelope{Format: ContainerDocker, Stream: record.Stream}
+	if t, err := time.Parse(time.RFC3339Nano, record.Time); err == nil {
+		envelope.Timestamp = t
+	}
+	text, full := strings.CutSuffix(record.Log, "\n")
+	text = strings.TrimSuffix(text, "\r")
+	return envelope, text, !full, true
+}
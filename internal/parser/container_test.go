@@ -0,0 +1,229 @@
+package parser
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aadithyaa9/loganalyzer/internal/models"
+)
+
+// unwrapped is what the container tests compare of a record
+type unwrapped struct {
+	text   string
+	stream string // "" for lines without an envelope
+	line   int
+}
+
+func TestContainerUnwrapper(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  ContainerFormat
+		lines   []string
+		records []unwrapped // Returned by Unwrap, in order
+		flushed []unwrapped // Returned by Flush at the end
+	}{
+		{
+			name:   "CRI full lines",
+			format: ContainerCRI,
+			lines: []string{
+				"2024-01-15T10:00:00.000000001Z stdout F first line",
+				"2024-01-15T10:00:01.5+02:00 stderr F second line",
+			},
+			records: []unwrapped{{"first line", "stdout", 1}, {"second line", "stderr", 2}},
+		},
+		{
+			name:   "CRI partial records",
+			format: ContainerCRI,
+			lines: []string{
+				"2024-01-15T10:00:00Z stdout P a very ",
+				"2024-01-15T10:00:00Z stdout P long ",
+				"2024-01-15T10:00:01Z stdout F line",
+			},
+			records: []unwrapped{{"a very long line", "stdout", 1}},
+		},
+		{
+			name:   "CRI partial records of two streams interleaved",
+			format: ContainerCRI,
+			lines: []string{
+				"2024-01-15T10:00:00Z stdout P out ",
+				"2024-01-15T10:00:00Z stderr F err",
+				"2024-01-15T10:00:01Z stdout F done",
+			},
+			records: []unwrapped{{"err", "stderr", 2}, {"out done", "stdout", 1}},
+		},
+		{
+			name:   "CRI extra tags and empty line",
+			format: ContainerCRI,
+			lines: []string{
+				"2024-01-15T10:00:00Z stdout F:x tagged",
+				"2024-01-15T10:00:01Z stdout F",
+			},
+			records: []unwrapped{{"tagged", "stdout", 1}, {"", "stdout", 2}},
+		},
+		{
+			name:   "CRI partial cut off at EOF",
+			format: ContainerCRI,
+			lines: []string{
+				"2024-01-15T10:00:00Z stdout F complete",
+				"2024-01-15T10:00:01Z stdout P never ",
+				"2024-01-15T10:00:01Z stdout P finished",
+			},
+			records: []unwrapped{{"complete", "stdout", 1}},
+			flushed: []unwrapped{{"never finished", "stdout", 2}},
+		},
+		{
+			name:   "CRI file with a foreign line",
+			format: ContainerCRI,
+			lines: []string{
+				"2024-01-15T10:00:00Z stdout F wrapped",
+				"not wrapped",
+			},
+			records: []unwrapped{{"wrapped", "stdout", 1}, {"not wrapped", "", 2}},
+		},
+		{
+			name:   "docker json-file",
+			format: ContainerDocker,
+			lines: []string{
+				`{"log":"hello\n","stream":"stdout","time":"2024-01-15T10:00:00.123456789Z"}`,
+				`{"log":"windows\r\n","stream":"stderr","time":"2024-01-15T10:00:01Z"}`,
+			},
+			records: []unwrapped{{"hello", "stdout", 1}, {"windows", "stderr", 2}},
+		},
+		{
+			name:   "docker partial records",
+			format: ContainerDocker,
+			lines: []string{
+				`{"log":"{\"level\":\"error\",","stream":"stdout","time":"2024-01-15T10:00:00Z"}`,
+				`{"log":"\"msg\":\"boom\"}\n","stream":"stdout","time":"2024-01-15T10:00:00Z"}`,
+			},
+			records: []unwrapped{{`{"level":"error","msg":"boom"}`, "stdout", 1}},
+		},
+		{
+			name:   "docker partial cut off at EOF",
+			format: ContainerDocker,
+			lines: []string{
+				`{"log":"no newline","stream":"stdout","time":"2024-01-15T10:00:00Z"}`,
+			},
+			flushed: []unwrapped{{"no newline", "stdout", 1}},
+		},
+		{
+			name:   "plain lines pass through",
+			format: ContainerNone,
+			lines: []string{
+				"",
+				"2024-01-15 10:00:00 INFO hello",
+				`{"level":"info","msg":"not docker"}`,
+			},
+			records: []unwrapped{{"", "", 1}, {"2024-01-15 10:00:00 INFO hello", "", 2}, {`{"level":"info","msg":"not docker"}`, "", 3}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := NewContainerUnwrapper()
+			var got []unwrapped
+			for i, line := range tt.lines {
+				r, ok := u.Unwrap(line, models.Position{Line: i + 1})
+				if !ok {
+					continue
+				}
+				got = append(got, toUnwrapped(r))
+			}
+			if u.Format() != tt.format {
+				t.Errorf("Format() = %s, want %s", u.Format(), tt.format)
+			}
+			checkRecords(t, "Unwrap", got, tt.records)
+
+			got = nil
+			for {
+				r, ok := u.Flush()
+				if !ok {
+					break
+				}
+				got = append(got, toUnwrapped(r))
+			}
+			checkRecords(t, "Flush", got, tt.flushed)
+		})
+	}
+}
+
+// toUnwrapped reduces a record to what the tests compare
+func toUnwrapped(r Record) unwrapped {
+	u := unwrapped{text: r.Text, line: r.Position.Line}
+	if r.Envelope != nil {
+		u.stream = r.Envelope.Stream
+	}
+	return u
+}
+
+func checkRecords(t *testing.T, what string, got, want []unwrapped) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Errorf("%s returned %d records %+v, want %+v", what, len(got), got, want)
+		return
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			t.Errorf("%s record %d = %+v, want %+v", what, i, got[i], want[i])
+		}
+	}
+}
+
+func TestContainerEnvelopeApply(t *testing.T) {
+	runtime := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	own := time.Date(2024, 1, 15, 9, 59, 59, 0, time.UTC)
+	envelope := &ContainerEnvelope{Format: ContainerCRI, Timestamp: runtime, Stream: "stderr"}
+
+	tests := []struct {
+		status models.TimestampStatus
+		want   time.Time
+	}{
+		{models.TimestampParsed, own},
+		{models.TimestampMissing, runtime},
+		{models.TimestampInvalid, runtime},
+	}
+	for _, tt := range tests {
+		entry := &models.LogEntry{Timestamp: own, TimestampStatus: tt.status}
+		envelope.Apply(entry)
+		if !entry.Timestamp.Equal(tt.want) || entry.TimestampStatus != models.TimestampParsed {
+			t.Errorf("%s timestamp: got %s (%s), want %s", tt.status, entry.Timestamp, entry.TimestampStatus, tt.want)
+		}
+		if stream, _ := entry.Fields.GetString("stream"); stream != "stderr" {
+			t.Errorf("%s timestamp: fields.stream = %q, want stderr", tt.status, stream)
+		}
+	}
+}
+
+func TestContainerPathFields(t *testing.T) {
+	id := "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+	tests := []struct {
+		path string
+		want models.Fields
+	}{
+		{
+			"/var/log/pods/prod_api-7d9f_1b2c3d4e-0000-1111-2222-333344445555/api/0.log",
+			models.Fields{"namespace": "prod", "pod": "api-7d9f", "pod_uid": "1b2c3d4e-0000-1111-2222-333344445555", "container": "api"},
+		},
+		{
+			"/var/log/containers/api-7d9f_prod_api-" + id + ".log",
+			models.Fields{"pod": "api-7d9f", "namespace": "prod", "container": "api", "container_id": id},
+		},
+		{
+			"/var/lib/docker/containers/" + id + "/" + id + "-json.log",
+			models.Fields{"container_id": id},
+		},
+		{"/var/log/app.log", nil},
+	}
+	for _, tt := range tests {
+		got := ContainerPathFields(tt.path)
+		if len(got) != len(tt.want) {
+			t.Errorf("ContainerPathFields(%q) = %v, want %v", tt.path, got, tt.want)
+			continue
+		}
+		for key, value := range tt.want {
+			if got[key] != value {
+				t.Errorf("ContainerPathFields(%q)[%q] = %v, want %v", tt.path, key, got[key], value)
+			}
+		}
+	}
+}
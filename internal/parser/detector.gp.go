@@ -2,28 +2,38 @@ package parser
 
 import "strings"
 
-// DetectParser automatically detects the appropriate parser for a log line
-func DetectParser(line string) LogParser {
-	line = strings.TrimSpace(line)
+// Detector picks the parser for a line from the registered parsers, trying
+// the most confident first and falling back to plain text
+type Detector struct {
+	candidates []candidate // Most confident first, fallback excluded
+	fallback   LogParser
+}
 
-	// Try JSON parser first
-	jsonParser := &JSONLogParser{}
-	if jsonParser.CanParse(line) {
-		return jsonParser
+// NewDetector creates a detector over every parser the options configure
+func NewDetector(opts Options) *Detector {
+	d := &Detector{}
+	for _, c := range candidates(opts) {
+		if c.fallback {
+			if d.fallback == nil {
+				d.fallback = c.parser
+			}
+			continue
+		}
+		d.candidates = append(d.candidates, c)
 	}
-
-	// Fallback to plain text parser
-	return &PlainTextLogParser{}
+	if d.fallback == nil {
+		d.fallback = &PlainTextLogParser{}
+	}
+	return d
 }
 
-// GetParser returns a parser based on the specified type
-func GetParser(parserType ParserType) LogParser {
-	switch parserType {
-	case JSONParser:
-		return &JSONLogParser{}
-	case PlainTextParser:
-		return &PlainTextLogParser{}
-	default:
-		return &PlainTextLogParser{}
+// Detect returns the most confident parser that accepts the line
+func (d *Detector) Detect(line string) LogParser {
+	line = strings.TrimSpace(line)
+	for _, c := range d.candidates {
+		if c.parser.CanParse(line) {
+			return c.parser
+		}
 	}
+	return d.fallback
 }
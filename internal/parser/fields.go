@@ -0,0 +1,39 @@
+package parser
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/aadithyaa9/loganalyzer/internal/models"
+)
+
+// keyValuePair matches key=value fragments in free text, with optional quoting
+var keyValuePair = regexp.MustCompile(`(?:^|[\s,;(\[])([A-Za-z_][\w.-]*)=("(?:[^"\\]|\\.)*"|'[^']*'|[^\s,;)\]]+)`)
+
+// extractKeyValues collects key=value fragments from a message into Fields
+func extractKeyValues(message string) models.Fields {
+	matches := keyValuePair.FindAllStringSubmatch(message, -1)
+	if len(matches) == 0 {
+		return nil
+	}
+
+	fields := make(models.Fields, len(matches))
+	for _, m := range matches {
+		key, value := m[1], m[2]
+
+		switch {
+		case strings.HasPrefix(value, `"`):
+			if unquoted, err := strconv.Unquote(value); err == nil {
+				fields[key] = unquoted
+			} else {
+				fields[key] = strings.Trim(value, `"`)
+			}
+		case strings.HasPrefix(value, "'"):
+			fields[key] = strings.Trim(value, "'")
+		default:
+			fields.SetInferred(key, value)
+		}
+	}
+	return fields
+}
@@ -0,0 +1,306 @@
+package parser
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aadithyaa9/loganalyzer/internal/models"
+)
+
+func init() {
+	// A grok expression is given for one specific format, so it is trusted
+	// above everything but JSON; without one the parser is left out
+	Register(Registration{
+		Name:       "grok",
+		Confidence: 0.98,
+		New: func(opts Options) (LogParser, error) {
+			if opts.GrokExpression == "" {
+				return nil, fmt.Errorf("%w: grok needs an expression", ErrNotConfigured)
+			}
+			return NewGrokLogParser(opts.GrokExpression, opts.GrokPatterns)
+		},
+	})
+}
+
+// grokReference matches %{PATTERN}, %{PATTERN:field} and %{PATTERN:field:type}
+var grokReference = regexp.MustCompile(`%\{(\w+)(?::([\w.@\[\]-]+))?(?::(int|float|string))?\}`)
+
+// Captures read into the fixed LogEntry fields; all others become Fields
+var (
+	grokTimestampNames = []string{"timestamp", "ts", "time", "@timestamp", "datetime"}
+	grokLevelNames     = []string{"level", "lvl", "loglevel", "severity"}
+	grokMessageNames   = []string{"msg", "message"}
+)
+
+// grokTimeFormats are the timestamp layouts produced by the bundled patterns
+// in addition to those ParseTimestampInLocation knows
+var grokTimeFormats = []string{
+	"2006-01-02 15:04:05,000",
+	"2006-01-02T15:04:05,000",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02T15:04:05.999999999-0700",
+	"2006-01-02 15:04:05.999999999-0700",
+	accessTimeFormat,
+	"01/02/2006 15:04:05",
+	"02.01.2006 15:04:05",
+}
+
+// grokCapture describes one named capture of a compiled expression
+type grokCapture struct {
+	field string
+	kind  string // "", "int", "float" or "string"
+}
+
+// GrokLogParser parses lines with a grok expression: a regular expression
+// with %{PATTERN:field} references into a pattern library
+type GrokLogParser struct {
+	expression string
+	re         *regexp.Regexp
+	captures   map[string]grokCapture // By regexp group name
+}
+
+// NewGrokLogParser compiles a grok expression against the bundled patterns
+// plus any user patterns, which take precedence
+func NewGrokLogParser(expression string, userPatterns map[string]string) (*GrokLogParser, error) {
+	patterns := make(map[string]string, len(grokPatterns)+len(userPatterns))
+	for name, pattern := range grokPatterns {
+		patterns[name] = pattern
+	}
+	for name, pattern := range userPatterns {
+		patterns[name] = pattern
+	}
+
+	p := &GrokLogParser{
+		expression: expression,
+		captures:   make(map[string]grokCapture),
+	}
+	expanded, err := p.expand(expression, patterns, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// (?s) lets GREEDYDATA span the continuation lines of multiline entries
+	re, err := regexp.Compile("(?s)" + expanded)
+	if err != nil {
+		return nil, fmt.Errorf("invalid grok expression: %w", err)
+	}
+	p.re = re
+	return p, nil
+}
+
+// expand replaces pattern references recursively. Named references become
+// generated capture groups so field names need not be valid group names.
+func (p *GrokLogParser) expand(expression string, patterns map[string]string, stack []string) (string, error) {
+	var expandErr error
+	expanded := grokReference.ReplaceAllStringFunc(expression, func(ref string) string {
+		if expandErr != nil {
+			return ""
+		}
+		m := grokReference.FindStringSubmatch(ref)
+		name, field, kind := m[1], m[2], m[3]
+
+		for _, seen := range stack {
+			if seen == name {
+				expandErr = fmt.Errorf("grok pattern %s references itself (%s)",
+					name, strings.Join(append(stack, name), " -> "))
+				return ""
+			}
+		}
+		pattern, ok := patterns[name]
+		if !ok {
+			expandErr = fmt.Errorf("unknown grok pattern %%{%s}", name)
+			return ""
+		}
+
+		inner, err := p.expand(pattern, patterns, append(stack, name))
+		if err != nil {
+			expandErr = err
+			return ""
+		}
+		if field == "" {
+			return "(?:" + inner + ")"
+		}
+
+		group := "g" + strconv.Itoa(len(p.captures))
+		p.captures[group] = grokCapture{field: field, kind: kind}
+		return "(?P<" + group + ">" + inner + ")"
+	})
+	return expanded, expandErr
+}
+
+// Parse parses a line with the grok expression
+func (p *GrokLogParser) Parse(line string, source string) (*models.LogEntry, error) {
+	if strings.TrimSpace(line) == "" {
+		return nil, ErrEmptyLine
+	}
+
+	m := p.re.FindStringSubmatch(line)
+	if m == nil {
+		return nil, fmt.Errorf("%w: line does not match grok expression", ErrInvalidFormat)
+	}
+
+	values := make(map[string]string)
+	fields := make(models.Fields)
+	for i, group := range p.re.SubexpNames() {
+		capture, ok := p.captures[group]
+		if !ok || m[i] == "" {
+			continue
+		}
+		if isGrokEntryName(capture.field) {
+			values[capture.field] = m[i]
+			continue
+		}
+		setGrokField(fields, capture, m[i])
+	}
+
+	timestamp, status := time.Now(), models.TimestampMissing // Fallback to now
+	if s := firstGrokValue(values, grokTimestampNames); s != "" {
+		if t, err := parseGrokTimestamp(s); err == nil {
+			timestamp, status = t, models.TimestampParsed
+		} else {
+			status = models.TimestampInvalid
+		}
+	}
+
+	level := models.UNKNOWN
+	if s := firstGrokValue(values, grokLevelNames); s != "" {
+		level = models.ParseLogLevel(strings.ToUpper(s))
+	}
+
+	// Without a message capture the whole line is the message
+	message := firstGrokValue(values, grokMessageNames)
+	if message == "" {
+		message = line
+	}
+
+	if len(fields) == 0 {
+		fields = nil
+	}
+
+	return &models.LogEntry{
+		Timestamp:       timestamp,
+		TimestampStatus: status,
+		Level:           level,
+		Message:         message,
+		Source:          source,
+		Raw:             line,
+		Fields:          fields,
+	}, nil
+}
+
+// CanParse checks if a line matches the grok expression
+func (p *GrokLogParser) CanParse(line string) bool {
+	return p.re.MatchString(line)
+}
+
+// Name returns the parser name
+func (p *GrokLogParser) Name() string {
+	return "Grok"
+}
+
+// String returns the grok expression
+func (p *GrokLogParser) String() string {
+	return p.expression
+}
+
+// LoadGrokPatterns reads pattern files with one "NAME regex" definition
+// per line, in the format of the logstash patterns directory. Blank lines
+// and lines starting with # are ignored.
+func LoadGrokPatterns(paths ...string) (map[string]string, error) {
+	patterns := make(map[string]string)
+	for _, path := range paths {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open grok patterns: %w", err)
+		}
+
+		scanner := bufio.NewScanner(file)
+		lineNum := 0
+		for scanner.Scan() {
+			lineNum++
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			name, pattern, ok := strings.Cut(line, " ")
+			if !ok || strings.TrimSpace(pattern) == "" {
+				file.Close()
+				return nil, fmt.Errorf("%s:%d: expected \"NAME pattern\"", path, lineNum)
+			}
+			patterns[name] = strings.TrimSpace(pattern)
+		}
+		err = scanner.Err()
+		file.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read grok patterns %s: %w", path, err)
+		}
+	}
+	return patterns, nil
+}
+
+// isGrokEntryName reports whether a capture feeds a fixed LogEntry field
+func isGrokEntryName(name string) bool {
+	for _, names := range [][]string{grokTimestampNames, grokLevelNames, grokMessageNames} {
+		for _, n := range names {
+			if n == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// firstGrokValue removes the given captures from values and returns the
+// first non-empty one
+func firstGrokValue(values map[string]string, names []string) string {
+	result := ""
+	for _, name := range names {
+		if value, ok := values[name]; ok {
+			delete(values, name)
+			if result == "" {
+				result = value
+			}
+		}
+	}
+	return result
+}
+
+// setGrokField stores a capture, converting it as its :int/:float suffix says
+func setGrokField(fields models.Fields, capture grokCapture, value string) {
+	switch capture.kind {
+	case "int", "float":
+		if n, err := strconv.ParseFloat(value, 64); err == nil {
+			fields[capture.field] = n
+			return
+		}
+		fields[capture.field] = value
+	case "string":
+		fields[capture.field] = value
+	default:
+		fields.SetInferred(capture.field, value)
+	}
+}
+
+// parseGrokTimestamp parses the timestamp layouts the bundled patterns match
+func parseGrokTimestamp(s string) (time.Time, error) {
+	if t, err := parseTimestamp(s); err == nil {
+		return t, nil
+	}
+	for _, format := range grokTimeFormats {
+		if t, err := time.Parse(format, s); err == nil {
+			return t, nil
+		}
+	}
+	// SYSLOGTIMESTAMP carries no year
+	if t, err := (&SyslogLogParser{}).parseBSDTimestamp(s, time.Now()); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("unable to parse timestamp: %s", s)
+}
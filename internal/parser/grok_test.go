@@ -0,0 +1,159 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aadithyaa9/loganalyzer/internal/models"
+)
+
+func TestGrokLogParser(t *testing.T) {
+	tests := []struct {
+		name       string
+		expression string
+		patterns   map[string]string
+		line       string
+		time       time.Time
+		level      models.LogLevel
+		message    string
+		fields     map[string]any
+		wantErr    bool
+	}{
+		{
+			name:       "timestamp, level, thread and message",
+			expression: `%{TIMESTAMP_ISO8601:ts} %{LOGLEVEL:level} \[%{DATA:thread}\] %{GREEDYDATA:msg}`,
+			line:       "2024-01-15 10:00:00,123 ERROR [main] Connection refused",
+			time:       time.Date(2024, 1, 15, 10, 0, 0, 123e6, time.UTC),
+			level:      models.ERROR,
+			message:    "Connection refused",
+			fields:     map[string]any{"thread": "main"},
+		},
+		{
+			name:       "typed captures",
+			expression: `%{WORD:method} %{URIPATHPARAM:path} %{NUMBER:status:int} %{NUMBER:took:float}ms id=%{NUMBER:id:string}`,
+			line:       "GET /api/v1?x=1 503 12.5ms id=007",
+			level:      models.UNKNOWN,
+			message:    "GET /api/v1?x=1 503 12.5ms id=007",
+			fields:     map[string]any{"method": "GET", "path": "/api/v1?x=1", "status": 503.0, "took": 12.5, "id": "007"},
+		},
+		{
+			name:       "apache combined",
+			expression: `%{COMBINEDAPACHELOG}`,
+			line:       `127.0.0.1 - - [10/Oct/2000:13:55:36 -0700] "GET / HTTP/1.1" 200 612 "-" "curl/8.0"`,
+			time:       time.Date(2000, 10, 10, 20, 55, 36, 0, time.UTC),
+			level:      models.UNKNOWN,
+			fields:     map[string]any{"verb": "GET", "response": 200.0, "agent": `"curl/8.0"`},
+		},
+		{
+			name:       "user patterns override the bundled ones",
+			expression: `%{LOGLEVEL:level} %{TICKET:ticket}`,
+			patterns:   map[string]string{"TICKET": `[A-Z]+-\d+`, "LOGLEVEL": `(?:HIGH|LOW)`},
+			line:       "HIGH OPS-42",
+			level:      models.UNKNOWN,
+			message:    "HIGH OPS-42",
+			fields:     map[string]any{"ticket": "OPS-42"},
+		},
+		{
+			name:       "GREEDYDATA spans continuation lines",
+			expression: `%{LOGLEVEL:level} %{GREEDYDATA:msg}`,
+			line:       "ERROR boom\n\tat Main.main(Main.java:1)",
+			level:      models.ERROR,
+			message:    "boom\n\tat Main.main(Main.java:1)",
+		},
+		{
+			name:       "no match",
+			expression: `%{TIMESTAMP_ISO8601:ts} %{LOGLEVEL:level}`,
+			line:       "not a match",
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := NewGrokLogParser(tt.expression, tt.patterns)
+			if err != nil {
+				t.Fatalf("NewGrokLogParser(%q): %v", tt.expression, err)
+			}
+			entry, err := p.Parse(tt.line, "app.log")
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Parse(%q) succeeded, want an error", tt.line)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.line, err)
+			}
+			if !tt.time.IsZero() && !entry.Timestamp.Equal(tt.time) {
+				t.Errorf("timestamp = %s, want %s", entry.Timestamp, tt.time)
+			}
+			if entry.Level != tt.level {
+				t.Errorf("level = %s, want %s", entry.Level, tt.level)
+			}
+			if tt.message != "" && entry.Message != tt.message {
+				t.Errorf("message = %q, want %q", entry.Message, tt.message)
+			}
+			for key, want := range tt.fields {
+				if got, ok := entry.Fields.Get(key); !ok || got != want {
+					t.Errorf("fields[%q] = %v (%T), want %v", key, got, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestGrokCompileErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		expression string
+		patterns   map[string]string
+	}{
+		{"unknown pattern", `%{NOPE:x}`, nil},
+		{"recursive pattern", `%{A}`, map[string]string{"A": `x%{B}`, "B": `%{A}`}},
+		{"invalid regex", `%{WORD:w}(`, nil},
+	}
+
+	for _, tt := range tests {
+		if _, err := NewGrokLogParser(tt.expression, tt.patterns); err == nil {
+			t.Errorf("%s: NewGrokLogParser(%q) succeeded, want an error", tt.name, tt.expression)
+		}
+	}
+}
+
+func TestLoadGrokPatterns(t *testing.T) {
+	dir := t.TempDir()
+	good := filepath.Join(dir, "good")
+	if err := os.WriteFile(good, []byte("# comment\n\nTICKET [A-Z]+-\\d+\nQUEUE  q-%{INT}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	patterns, err := LoadGrokPatterns(good)
+	if err != nil {
+		t.Fatalf("LoadGrokPatterns: %v", err)
+	}
+	if patterns["TICKET"] != `[A-Z]+-\d+` || patterns["QUEUE"] != "q-%{INT}" || len(patterns) != 2 {
+		t.Errorf("LoadGrokPatterns = %q", patterns)
+	}
+
+	bad := filepath.Join(dir, "bad")
+	if err := os.WriteFile(bad, []byte("LONELY\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadGrokPatterns(bad); err == nil {
+		t.Errorf("LoadGrokPatterns(%q) succeeded, want an error", bad)
+	}
+}
+
+func TestGrokRegistry(t *testing.T) {
+	if _, err := New("grok", Options{}); err == nil {
+		t.Errorf("New(grok) without an expression succeeded, want an error")
+	}
+	p, err := New("grok", Options{GrokExpression: `%{LOGLEVEL:level} %{GREEDYDATA:msg}`})
+	if err != nil {
+		t.Fatalf("New(grok): %v", err)
+	}
+	if !p.CanParse("WARN disk almost full") {
+		t.Errorf("CanParse = false, want true")
+	}
+}
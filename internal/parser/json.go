@@ -9,16 +9,19 @@ import (
 	"github.com/aadithyaa9/loganalyzer/internal/models"
 )
 
+func init() {
+	Register(Registration{
+		Name:       "json",
+		Confidence: 1.0,
+		New: func(opts Options) (LogParser, error) {
+			return &JSONLogParser{Profile: opts.JSONProfile}, nil
+		},
+	})
+}
+
 // JSONLogParser parses JSON-formatted logs
-type JSONLogParser struct{}
-
-// jsonLogEntry represents the JSON structure
-type jsonLogEntry struct {
-	Timestamp string `json:"timestamp"`
-	Time      string `json:"time"`
-	Level     string `json:"level"`
-	Message   string `json:"message"`
-	Msg       string `json:"msg"`
+type JSONLogParser struct {
+	Profile *JSONProfile // Key mapping; nil means DefaultJSONProfile
 }
 
 // Parse parses a JSON log line
@@ -27,35 +30,68 @@ func (p *JSONLogParser) Parse(line string, source string) (*models.LogEntry, err
 		return nil, ErrEmptyLine
 	}
 
-	var jsonEntry jsonLogEntry
-	if err := json.Unmarshal([]byte(line), &jsonEntry); err != nil {
+	decoder := json.NewDecoder(strings.NewReader(line))
+	decoder.UseNumber()
+
+	var object map[string]any
+	if err := decoder.Decode(&object); err != nil {
 		return nil, fmt.Errorf("%w: %v", ErrInvalidFormat, err)
 	}
+	// The object must be the whole line
+	if rest := strings.TrimSpace(line[decoder.InputOffset():]); rest != "" {
+		return nil, fmt.Errorf("%w: trailing data after JSON object: %q", ErrInvalidFormat, rest)
+	}
+
+	profile := p.Profile
+	if profile == nil {
+		profile = DefaultJSONProfile
+	}
+
+	// Parse timestamp (strings in multiple formats, or epoch numbers)
+	timestamp, status := time.Now(), models.TimestampMissing // Fallback to now
+	if value, ok := takePath(object, profile.Timestamp); ok {
+		if t, err := profile.timestamp(value); err == nil {
+			timestamp, status = t, models.TimestampParsed
+		} else {
+			status = models.TimestampInvalid
+		}
+	}
 
-	// Handle different timestamp fields
-	timestampStr := jsonEntry.Timestamp
-	if timestampStr == "" {
-		timestampStr = jsonEntry.Time
+	level := models.UNKNOWN
+	if value, ok := takePath(object, profile.Level); ok {
+		level = profile.level(value)
 	}
 
-	// Parse timestamp (try multiple formats)
-	timestamp, err := parseTimestamp(timestampStr)
-	if err != nil {
-		timestamp = time.Now() // Fallback to now
+	message := ""
+	if value, ok := takePath(object, profile.Message); ok {
+		message = models.FormatFieldValue(value)
 	}
 
-	// Handle different message fields
-	message := jsonEntry.Message
-	if message == "" {
-		message = jsonEntry.Msg
+	if value, ok := takePath(object, profile.Source); ok {
+		source = models.FormatFieldValue(value)
+	}
+	service, hasService := takePath(object, profile.Service)
+
+	// Everything else is kept as structured fields
+	var fields models.Fields
+	if len(object) > 0 || hasService {
+		fields = make(models.Fields, len(object)+1)
+		for key, value := range object {
+			fields.Set(key, value)
+		}
+	}
+	if hasService {
+		fields["service"] = models.FormatFieldValue(service)
 	}
 
 	entry := &models.LogEntry{
-		Timestamp: timestamp,
-		Level:     models.ParseLogLevel(strings.ToUpper(jsonEntry.Level)),
-		Message:   message,
-		Source:    source,
-		Raw:       line,
+		Timestamp:       timestamp,
+		TimestampStatus: status,
+		Level:           level,
+		Message:         message,
+		Source:          source,
+		Raw:             line,
+		Fields:          fields,
 	}
 
 	return entry, nil
@@ -77,6 +113,12 @@ func (p *JSONLogParser) Name() string {
 
 // parseTimestamp tries to parse timestamp in multiple formats
 func parseTimestamp(s string) (time.Time, error) {
+	return ParseTimestampInLocation(s, time.UTC)
+}
+
+// ParseTimestampInLocation parses a timestamp in any of the supported formats.
+// Formats without a zone offset are interpreted in loc.
+func ParseTimestampInLocation(s string, loc *time.Location) (time.Time, error) {
 	formats := []string{
 		time.RFC3339,
 		time.RFC3339Nano,
@@ -86,7 +128,7 @@ func parseTimestamp(s string) (time.Time, error) {
 	}
 
 	for _, format := range formats {
-		if t, err := time.Parse(format, s); err == nil {
+		if t, err := time.ParseInLocation(format, s, loc); err == nil {
 			return t, nil
 		}
 	}
@@ -0,0 +1,125 @@
+package parser
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aadithyaa9/loganalyzer/internal/models"
+)
+
+func TestJSONLogParser(t *testing.T) {
+	tests := []struct {
+		name    string
+		line    string
+		level   models.LogLevel
+		message string
+		wantErr bool
+	}{
+		{"object", `{"time":"2024-01-15T10:00:00Z","level":"error","msg":"disk full"}`, models.ERROR, "disk full", false},
+		{"surrounding whitespace", `  {"level":"warn","msg":"slow"}  `, models.WARN, "slow", false},
+		{"no level", `{"msg":"hello"}`, models.UNKNOWN, "hello", false},
+		{"trailing garbage", `{"level":"info","msg":"a"} trailing`, 0, "", true},
+		{"two objects", `{"msg":"a"}{"msg":"b"}`, 0, "", true},
+		{"truncated", `{"msg":"a"`, 0, "", true},
+		{"not an object", `["a"]`, 0, "", true},
+	}
+
+	p := &JSONLogParser{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry, err := p.Parse(tt.line, "app.log")
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidFormat) {
+					t.Fatalf("Parse(%q) error = %v, want ErrInvalidFormat", tt.line, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.line, err)
+			}
+			if entry.Level != tt.level || entry.Message != tt.message {
+				t.Errorf("Parse(%q) = %s %q, want %s %q", tt.line, entry.Level, entry.Message, tt.level, tt.message)
+			}
+		})
+	}
+}
+
+func TestJSONProfileService(t *testing.T) {
+	tests := []struct {
+		profile string
+		line    string
+		service string
+	}{
+		{"pino", `{"time":1705312800000,"level":50,"name":"orders","msg":"payment failed"}`, "orders"},
+		{"zap", `{"ts":1705312800.5,"level":"error","logger":"db","msg":"timeout"}`, "db"},
+		{"ecs", `{"@timestamp":"2024-01-15T10:00:00Z","log.level":"error","service":{"name":"api"},"message":"boom"}`, "api"},
+		{"default", `{"level":"error","name":"orders","msg":"x"}`, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.profile, func(t *testing.T) {
+			profile, err := LoadJSONProfile(tt.profile)
+			if err != nil {
+				t.Fatal(err)
+			}
+			entry, err := (&JSONLogParser{Profile: profile}).Parse(tt.line, "app.log")
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.line, err)
+			}
+			if entry.Source != "app.log" {
+				t.Errorf("source = %q, want the file name", entry.Source)
+			}
+			if entry.Level != models.ERROR {
+				t.Errorf("level = %s, want ERROR", entry.Level)
+			}
+			service, ok := entry.Fields.GetString("service")
+			if tt.service == "" {
+				if ok {
+					t.Errorf("fields.service = %q, want it absent", service)
+				}
+				return
+			}
+			if service != tt.service {
+				t.Errorf("fields.service = %q, want %q", service, tt.service)
+			}
+		})
+	}
+}
+
+func TestJSONProfileSource(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "profile.json")
+	if err := os.WriteFile(path, []byte(`{"extends": "ecs", "source": ["host.name"]}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	profile, err := LoadJSONProfile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &JSONLogParser{Profile: profile}
+
+	line := `{"@timestamp":"2024-01-15T10:00:00Z","log.level":"error","host":{"name":"web-1"},"service":{"name":"api"},"message":"boom"}`
+	entry, err := p.Parse(line, "app.log")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry.Source != "web-1" {
+		t.Errorf("source = %q, want web-1 from host.name", entry.Source)
+	}
+	if service, _ := entry.Fields.GetString("service"); service != "api" {
+		t.Errorf("fields.service = %q, want api", service)
+	}
+	if _, ok := entry.Fields["host"]; ok {
+		t.Errorf("fields = %v, want host.name removed", entry.Fields)
+	}
+
+	// Without the key, the source stays the file
+	entry, err = p.Parse(`{"log.level":"info","message":"ok"}`, "app.log")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry.Source != "app.log" {
+		t.Errorf("source = %q, want app.log", entry.Source)
+	}
+}
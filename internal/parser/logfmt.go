@@ -0,0 +1,251 @@
+package parser
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aadithyaa9/loganalyzer/internal/models"
+)
+
+func init() {
+	Register(Registration{
+		Name:       "logfmt",
+		Confidence: 0.8,
+		New: func(opts Options) (LogParser, error) {
+			return &LogfmtLogParser{Keys: opts.LogfmtKeys}, nil
+		},
+	})
+}
+
+// KeyAliases lists the keys read into the fixed LogEntry fields, in order
+// of preference. All other keys are kept as structured fields.
+type KeyAliases struct {
+	Time    []string
+	Level   []string
+	Message []string
+}
+
+// DefaultLogfmtKeys are the keys used by go-kit, logrus, zerolog and slog
+var DefaultLogfmtKeys = KeyAliases{
+	Time:    []string{"ts", "time", "timestamp", "t"},
+	Level:   []string{"level", "lvl", "severity"},
+	Message: []string{"msg", "message"},
+}
+
+// ParseKeyAliases parses a comma-separated list of attribute=key pairs, e.g.
+// "time=at,level=severity,msg=text". Repeating an attribute adds further
+// candidate keys; attributes left out keep their default keys.
+func ParseKeyAliases(spec string) (KeyAliases, error) {
+	var aliases KeyAliases
+	for _, pair := range strings.Split(spec, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		attribute, key, ok := strings.Cut(pair, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return KeyAliases{}, fmt.Errorf("invalid key alias %q (expected attribute=key)", pair)
+		}
+
+		switch strings.ToLower(strings.TrimSpace(attribute)) {
+		case "time", "ts", "timestamp":
+			aliases.Time = append(aliases.Time, key)
+		case "level", "lvl":
+			aliases.Level = append(aliases.Level, key)
+		case "msg", "message":
+			aliases.Message = append(aliases.Message, key)
+		default:
+			return KeyAliases{}, fmt.Errorf("unknown attribute %q in key alias %q (time, level, msg)", attribute, pair)
+		}
+	}
+	return aliases, nil
+}
+
+// LogfmtLogParser parses logfmt lines: space-separated key=value pairs with
+// optional double-quoted values
+type LogfmtLogParser struct {
+	Keys KeyAliases // Zero value means DefaultLogfmtKeys
+}
+
+// logfmtPair is one key=value pair; bare keys have no value
+type logfmtPair struct {
+	key      string
+	value    string
+	hasValue bool
+}
+
+// Parse parses a logfmt line
+// Expected format:
+// ts=2024-01-20T15:04:05Z level=error msg="db down" err="dial tcp: timeout" attempt=3
+func (p *LogfmtLogParser) Parse(line string, source string) (*models.LogEntry, error) {
+	if strings.TrimSpace(line) == "" {
+		return nil, ErrEmptyLine
+	}
+
+	pairs, err := scanLogfmt(line)
+	if err != nil {
+		return nil, err
+	}
+
+	object := make(map[string]string, len(pairs))
+	fields := make(models.Fields, len(pairs))
+	for _, pair := range pairs {
+		if !pair.hasValue {
+			// A bare key is a boolean flag
+			fields[pair.key] = true
+			continue
+		}
+		object[pair.key] = pair.value
+	}
+
+	keys := p.keys()
+	timestamp, status := time.Now(), models.TimestampMissing // Fallback to now
+	if value := takeLogfmtValue(object, keys.Time); value != "" {
+		if t, err := parseTimestamp(value); err == nil {
+			timestamp, status = t, models.TimestampParsed
+		} else {
+			status = models.TimestampInvalid
+		}
+	}
+	level := takeLogfmtValue(object, keys.Level)
+	message := takeLogfmtValue(object, keys.Message)
+
+	for key, value := range object {
+		fields.SetInferred(key, value)
+	}
+	if len(fields) == 0 {
+		fields = nil
+	}
+
+	return &models.LogEntry{
+		Timestamp:       timestamp,
+		TimestampStatus: status,
+		Level:           models.ParseLogLevel(strings.ToUpper(level)),
+		Message:         message,
+		Source:          source,
+		Raw:             line,
+		Fields:          fields,
+	}, nil
+}
+
+// CanParse checks if a line is logfmt: it must start with a key=value pair,
+// tokenize cleanly, and consist mostly of pairs rather than bare words
+func (p *LogfmtLogParser) CanParse(line string) bool {
+	line = strings.TrimSpace(line)
+	first, _, _ := strings.Cut(line, " ")
+	if eq := strings.IndexByte(first, '='); eq <= 0 {
+		return false
+	}
+
+	pairs, err := scanLogfmt(line)
+	if err != nil || len(pairs) < 2 {
+		return false
+	}
+
+	withValue := 0
+	for _, pair := range pairs {
+		if pair.hasValue {
+			withValue++
+		}
+	}
+	return withValue*2 > len(pairs)
+}
+
+// Name returns the parser name
+func (p *LogfmtLogParser) Name() string {
+	return "Logfmt"
+}
+
+// keys returns the configured aliases, falling back to the defaults
+func (p *LogfmtLogParser) keys() KeyAliases {
+	keys := p.Keys
+	if keys.Time == nil {
+		keys.Time = DefaultLogfmtKeys.Time
+	}
+	if keys.Level == nil {
+		keys.Level = DefaultLogfmtKeys.Level
+	}
+	if keys.Message == nil {
+		keys.Message = DefaultLogfmtKeys.Message
+	}
+	return keys
+}
+
+// takeLogfmtValue removes the given keys from object and returns the first
+// non-empty value
+func takeLogfmtValue(object map[string]string, keys []string) string {
+	result := ""
+	for _, key := range keys {
+		value, ok := object[key]
+		if !ok {
+			continue
+		}
+		delete(object, key)
+		if value != "" && result == "" {
+			result = value
+		}
+	}
+	return result
+}
+
+// scanLogfmt tokenizes a logfmt line. Keys run up to '=', a space or a quote;
+// quoted values support the escapes of Go string literals (\" \\ \n \t ...).
+func scanLogfmt(line string) ([]logfmtPair, error) {
+	var pairs []logfmtPair
+	i := 0
+	for i < len(line) {
+		if line[i] == ' ' || line[i] == '\t' {
+			i++
+			continue
+		}
+
+		start := i
+		for i < len(line) && line[i] > ' ' && line[i] != '=' && line[i] != '"' {
+			i++
+		}
+		key := line[start:i]
+		if key == "" {
+			return nil, fmt.Errorf("%w: expected a logfmt key at column %d", ErrInvalidFormat, start+1)
+		}
+
+		if i >= len(line) || line[i] != '=' {
+			if i < len(line) && line[i] == '"' {
+				return nil, fmt.Errorf("%w: unexpected quote in logfmt key at column %d", ErrInvalidFormat, i+1)
+			}
+			pairs = append(pairs, logfmtPair{key: key})
+			continue
+		}
+		i++ // Skip '='
+
+		if i < len(line) && line[i] == '"' {
+			end := i + 1
+			for end < len(line) && line[end] != '"' {
+				if line[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(line) {
+				return nil, fmt.Errorf("%w: unterminated quoted value for %q", ErrInvalidFormat, key)
+			}
+			value, err := strconv.Unquote(line[i : end+1])
+			if err != nil {
+				// Tolerate escapes Go does not know by keeping the text as is
+				value = line[i+1 : end]
+			}
+			pairs = append(pairs, logfmtPair{key: key, value: value, hasValue: true})
+			i = end + 1
+			continue
+		}
+
+		start = i
+		for i < len(line) && line[i] != ' ' && line[i] != '\t' {
+			i++
+		}
+		pairs = append(pairs, logfmtPair{key: key, value: line[start:i], hasValue: true})
+	}
+	return pairs, nil
+}
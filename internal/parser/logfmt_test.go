@@ -0,0 +1,164 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/aadithyaa9/loganalyzer/internal/models"
+)
+
+func TestLogfmtLogParser(t *testing.T) {
+	tests := []struct {
+		name    string
+		keys    KeyAliases
+		line    string
+		time    time.Time
+		level   models.LogLevel
+		message string
+		fields  map[string]any
+		absent  []string
+		wantErr bool
+	}{
+		{
+			name:    "go-kit style",
+			line:    `ts=2024-01-20T15:04:05Z level=error msg="db down" err="dial tcp: timeout" attempt=3`,
+			time:    time.Date(2024, 1, 20, 15, 4, 5, 0, time.UTC),
+			level:   models.ERROR,
+			message: "db down",
+			fields:  map[string]any{"err": "dial tcp: timeout", "attempt": 3.0},
+			absent:  []string{"ts", "level", "msg"},
+		},
+		{
+			name:    "aliases, escapes and bare keys",
+			line:    `time="2024-01-20 15:04:05" lvl=warn message="say \"hi\"\n" retry cached=false`,
+			time:    time.Date(2024, 1, 20, 15, 4, 5, 0, time.UTC),
+			level:   models.WARN,
+			message: "say \"hi\"\n",
+			fields:  map[string]any{"retry": true, "cached": false},
+		},
+		{
+			name:   "empty value",
+			line:   `level=info msg= user=""`,
+			level:  models.INFO,
+			fields: map[string]any{"user": ""},
+			absent: []string{"msg"},
+		},
+		{
+			name:    "configured keys",
+			keys:    KeyAliases{Time: []string{"at"}, Level: []string{"sev"}, Message: []string{"text"}},
+			line:    `at=2024-01-20T15:04:05Z sev=fatal text="out of memory" level=debug`,
+			time:    time.Date(2024, 1, 20, 15, 4, 5, 0, time.UTC),
+			level:   models.FATAL,
+			message: "out of memory",
+			fields:  map[string]any{"level": "debug"},
+		},
+		{
+			name:    "unterminated quote",
+			line:    `level=info msg="unterminated`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &LogfmtLogParser{Keys: tt.keys}
+			entry, err := p.Parse(tt.line, "app.log")
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Parse(%q) succeeded, want an error", tt.line)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.line, err)
+			}
+			if !tt.time.IsZero() && !entry.Timestamp.Equal(tt.time) {
+				t.Errorf("timestamp = %s, want %s", entry.Timestamp, tt.time)
+			}
+			if entry.Level != tt.level {
+				t.Errorf("level = %s, want %s", entry.Level, tt.level)
+			}
+			if entry.Message != tt.message {
+				t.Errorf("message = %q, want %q", entry.Message, tt.message)
+			}
+			for key, want := range tt.fields {
+				if got, ok := entry.Fields.Get(key); !ok || got != want {
+					t.Errorf("fields[%q] = %v, want %v", key, got, want)
+				}
+			}
+			for _, key := range tt.absent {
+				if got, ok := entry.Fields.Get(key); ok {
+					t.Errorf("fields[%q] = %v, want it absent", key, got)
+				}
+			}
+		})
+	}
+}
+
+func TestLogfmtCanParse(t *testing.T) {
+	tests := []struct {
+		line string
+		want bool
+	}{
+		{`ts=2024-01-20T15:04:05Z level=error msg="db down"`, true},
+		{`level=info ready`, false},
+		{`2024-01-20 15:04:05 INFO key=value`, false},
+		{`a=1 b c d`, false},
+		{`msg="unterminated`, false},
+	}
+
+	p := &LogfmtLogParser{}
+	for _, tt := range tests {
+		if got := p.CanParse(tt.line); got != tt.want {
+			t.Errorf("CanParse(%q) = %v, want %v", tt.line, got, tt.want)
+		}
+	}
+}
+
+func TestParseKeyAliases(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    KeyAliases
+		wantErr bool
+	}{
+		{"", KeyAliases{}, false},
+		{"time=at, level=severity,msg=text", KeyAliases{Time: []string{"at"}, Level: []string{"severity"}, Message: []string{"text"}}, false},
+		{"ts=when,time=at", KeyAliases{Time: []string{"when", "at"}}, false},
+		{"message=body", KeyAliases{Message: []string{"body"}}, false},
+		{"host=h", KeyAliases{}, true},
+		{"time", KeyAliases{}, true},
+		{"level=", KeyAliases{}, true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseKeyAliases(tt.spec)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseKeyAliases(%q) succeeded, want an error", tt.spec)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseKeyAliases(%q): %v", tt.spec, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseKeyAliases(%q) = %+v, want %+v", tt.spec, got, tt.want)
+		}
+	}
+}
+
+func TestLogfmtRegistryUsesKeys(t *testing.T) {
+	p, err := New("logfmt", Options{LogfmtKeys: KeyAliases{Level: []string{"sev"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry, err := p.Parse("sev=error msg=boom", "app.log")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry.Level != models.ERROR || entry.Message != "boom" {
+		t.Errorf("Parse = %s %q, want ERROR \"boom\"", entry.Level, entry.Message)
+	}
+}
@@ -0,0 +1,142 @@
+package parser
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Heuristics for recognizing continuation lines of multiline entries
+var (
+	// continuationLine matches stack trace lines, which always belong to the
+	// previous entry, indented or not
+	continuationLine = regexp.MustCompile(`^\s*(Caused by:|Suppressed:|at \S|\.\.\. \d+ (more|common frames omitted)|` +
+		`Traceback \(most recent call last\):|During handling of the above exception|The above exception was the direct cause|` +
+		`goroutine \d+ \[|` +
+		`([a-z_$][\w$]*\.)+[A-Z][\w$]*(Exception|Error|Throwable)\b)`)
+
+	// entryStartLine matches lines that look like the start of a new entry
+	entryStartLine = regexp.MustCompile(`^(\[?\d{4}[-/]\d{2}[-/]\d{2}|\{|(DEBUG|INFO|WARN|WARNING|ERROR|FATAL)\b|` +
+		`\S+ \S+ \S+ \[\d{2}/\w{3}/\d{4}:|<\d{1,3}>|[A-Z][a-z]{2} [ \d]\d \d{2}:\d{2}:\d{2} |(ts|time|level|lvl)=)`)
+
+	// pythonExceptionLine matches the final "ValueError: ..." line of a Python traceback
+	pythonExceptionLine = regexp.MustCompile(`^[A-Za-z_][\w.]*(Error|Exception|Exit|Interrupt|Warning)\b`)
+)
+
+// blockKind tracks which kind of multi-line dump is being assembled
+type blockKind int
+
+const (
+	noBlock blockKind = iota
+	pythonBlock
+	goroutineBlock
+)
+
+// MultilineAssembler folds continuation lines (stack frames, "Caused by:",
+// Python tracebacks, goroutine dumps) into the entry that precedes them.
+// It sits between the line scanner and LogParser.Parse.
+type MultilineAssembler struct {
+	start *regexp.Regexp // If set, only lines matching it begin a new entry
+	lines []string
+	block blockKind
+	trace bool // Whether the buffered entry has stack trace lines
+}
+
+// NewMultilineAssembler creates an assembler. If start is nil, built-in
+// heuristics decide which lines continue the previous entry.
+func NewMultilineAssembler(start *regexp.Regexp) *MultilineAssembler {
+	return &MultilineAssembler{start: start}
+}
+
+// Add feeds one line. When the line begins a new entry, the previously
+// buffered entry is returned as complete.
+func (m *MultilineAssembler) Add(line string) (string, bool) {
+	if len(m.lines) > 0 && m.isContinuation(line) {
+		m.lines = append(m.lines, line)
+		m.updateBlock(line)
+		return "", false
+	}
+
+	// Blank lines only matter inside an entry
+	if strings.TrimSpace(line) == "" {
+		return "", false
+	}
+
+	entry, ok := m.Flush()
+	m.lines = append(m.lines, line)
+	m.updateBlock(line)
+	return entry, ok
+}
+
+// Flush returns the buffered entry, if any, and resets the assembler
+func (m *MultilineAssembler) Flush() (string, bool) {
+	if len(m.lines) == 0 {
+		return "", false
+	}
+
+	// Drop trailing blank lines folded in while inside a dump
+	end := len(m.lines)
+	for end > 1 && strings.TrimSpace(m.lines[end-1]) == "" {
+		end--
+	}
+
+	entry := strings.Join(m.lines[:end], "\n")
+	m.lines = m.lines[:0]
+	m.block = noBlock
+	m.trace = false
+	return entry, true
+}
+
+// Pending reports whether an entry is buffered
+func (m *MultilineAssembler) Pending() bool {
+	return len(m.lines) > 0
+}
+
+// IsEntryStart reports whether a line certainly begins a new entry,
+// regardless of what came before it. Used to align parallel chunks.
+func (m *MultilineAssembler) IsEntryStart(line string) bool {
+	if m.start != nil {
+		return m.start.MatchString(line)
+	}
+	return entryStartLine.MatchString(line) && !continuationLine.MatchString(line)
+}
+
+// isContinuation decides whether a line belongs to the buffered entry
+func (m *MultilineAssembler) isContinuation(line string) bool {
+	if m.start != nil {
+		return !m.start.MatchString(line)
+	}
+
+	switch m.block {
+	case pythonBlock, goroutineBlock:
+		// Dumps run until something that looks like a fresh log line
+		return !entryStartLine.MatchString(line)
+	}
+
+	if strings.TrimSpace(line) == "" {
+		return false
+	}
+	// Other indented lines only continue an entry that has a stack trace,
+	// so indented log lines are not folded into whatever precedes them
+	return continuationLine.MatchString(line) || (m.trace && isIndented(line))
+}
+
+// isIndented reports whether a line starts with whitespace
+func isIndented(line string) bool {
+	return line != "" && (line[0] == ' ' || line[0] == '\t')
+}
+
+// updateBlock tracks entry into and exit from multi-line dumps and stack traces
+func (m *MultilineAssembler) updateBlock(line string) {
+	if continuationLine.MatchString(line) {
+		m.trace = true
+	}
+	switch {
+	case strings.HasPrefix(line, "Traceback (most recent call last):"):
+		m.block = pythonBlock
+	case strings.HasPrefix(line, "goroutine ") && continuationLine.MatchString(line):
+		m.block = goroutineBlock
+	case m.block == pythonBlock && pythonExceptionLine.MatchString(line):
+		// The unindented exception line closes the traceback
+		m.block = noBlock
+	}
+}
@@ -0,0 +1,120 @@
+package parser
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// assemble feeds lines through an assembler and returns the entries
+func assemble(start *regexp.Regexp, text string) []string {
+	m := NewMultilineAssembler(start)
+	var entries []string
+	for _, line := range strings.Split(text, "\n") {
+		if entry, ok := m.Add(line); ok {
+			entries = append(entries, entry)
+		}
+	}
+	if entry, ok := m.Flush(); ok {
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func TestMultilineAssembler(t *testing.T) {
+	tests := []struct {
+		name  string
+		start *regexp.Regexp
+		text  string
+		want  []string
+	}{
+		{
+			name: "java stack trace",
+			text: "2024-01-15 10:00:00 ERROR Request failed\n" +
+				"java.lang.IllegalStateException: boom\n" +
+				"\tat com.example.Service.run(Service.java:42)\n" +
+				"\tat com.example.Main.main(Main.java:7)\n" +
+				"Caused by: java.io.IOException: disk\n" +
+				"\t... 2 more\n" +
+				"2024-01-15 10:00:01 INFO Recovered",
+			want: []string{
+				"2024-01-15 10:00:00 ERROR Request failed\n" +
+					"java.lang.IllegalStateException: boom\n" +
+					"\tat com.example.Service.run(Service.java:42)\n" +
+					"\tat com.example.Main.main(Main.java:7)\n" +
+					"Caused by: java.io.IOException: disk\n" +
+					"\t... 2 more",
+				"2024-01-15 10:00:01 INFO Recovered",
+			},
+		},
+		{
+			name: "python traceback",
+			text: "2024-01-15 10:00:00 ERROR Job failed\n" +
+				"Traceback (most recent call last):\n" +
+				"  File \"job.py\", line 3, in <module>\n" +
+				"    run()\n" +
+				"ValueError: bad input\n" +
+				"2024-01-15 10:00:01 INFO Next job",
+			want: []string{
+				"2024-01-15 10:00:00 ERROR Job failed\n" +
+					"Traceback (most recent call last):\n" +
+					"  File \"job.py\", line 3, in <module>\n" +
+					"    run()\n" +
+					"ValueError: bad input",
+				"2024-01-15 10:00:01 INFO Next job",
+			},
+		},
+		{
+			name: "goroutine dump",
+			text: "panic: runtime error: index out of range\n" +
+				"\n" +
+				"goroutine 1 [running]:\n" +
+				"main.main()\n" +
+				"\t/app/main.go:12 +0x1d\n" +
+				"2024-01-15 10:00:01 INFO restarted",
+			want: []string{
+				"panic: runtime error: index out of range\n" +
+					"goroutine 1 [running]:\n" +
+					"main.main()\n" +
+					"\t/app/main.go:12 +0x1d",
+				"2024-01-15 10:00:01 INFO restarted",
+			},
+		},
+		{
+			name: "indented lines without a stack trace stay entries",
+			text: "2024-01-15 10:00:00 INFO config loaded\n" +
+				"  port: 8080\n" +
+				"  host: example.com",
+			want: []string{
+				"2024-01-15 10:00:00 INFO config loaded",
+				"  port: 8080",
+				"  host: example.com",
+			},
+		},
+		{
+			name:  "start pattern",
+			start: regexp.MustCompile(`^\d{4}-`),
+			text:  "2024-01-15 10:00:00 INFO query\n  SELECT *\n  FROM users\n2024-01-15 10:00:01 INFO done",
+			want:  []string{"2024-01-15 10:00:00 INFO query\n  SELECT *\n  FROM users", "2024-01-15 10:00:01 INFO done"},
+		},
+		{
+			name: "blank lines between entries are dropped",
+			text: "2024-01-15 10:00:00 INFO a\n\n2024-01-15 10:00:01 INFO b",
+			want: []string{"2024-01-15 10:00:00 INFO a", "2024-01-15 10:00:01 INFO b"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := assemble(tt.start, tt.text)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d entries, want %d:\n%q", len(got), len(tt.want), got)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("entry %d = %q, want %q", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package parser
 
 import (
 	"errors"
+
 	"github.com/aadithyaa9/loganalyzer/internal/models"
 )
 
@@ -18,25 +19,3 @@ type LogParser interface {
 	CanParse(line string) bool
 	Name() string
 }
-
-// ParserType represents different parser types (enum pattern)
-type ParserType int
-
-const (
-	JSONParser ParserType = iota
-	PlainTextParser
-	AutoDetect
-)
-
-func (p ParserType) String() string {
-	switch p {
-	case JSONParser:
-		return "JSON"
-	case PlainTextParser:
-		return "PlainText"
-	case AutoDetect:
-		return "AutoDetect"
-	default:
-		return "Unknown"
-	}
-}
@@ -8,6 +8,17 @@ import (
 	"github.com/aadithyaa9/loganalyzer/internal/models"
 )
 
+func init() {
+	Register(Registration{
+		Name:       "plain",
+		Confidence: 0.1,
+		Fallback:   true,
+		New: func(opts Options) (LogParser, error) {
+			return &PlainTextLogParser{}, nil
+		},
+	})
+}
+
 // PlainTextLogParser parses plain text logs
 type PlainTextLogParser struct{}
 
@@ -21,22 +32,28 @@ func (p *PlainTextLogParser) Parse(line string, source string) (*models.LogEntry
 	}
 
 	// Try to extract timestamp
+	status := models.TimestampParsed
 	timestamp, rest, err := extractTimestamp(line)
 	if err != nil {
 		// If no timestamp found, use current time
 		timestamp = time.Now()
+		status = models.TimestampMissing
 		rest = line
 	}
 
 	// Try to extract log level
-	level, message := extractLevelAndMessage(rest)
+	level, message, found := extractLevelAndMessage(rest)
+	message = strings.TrimSpace(message)
 
 	entry := &models.LogEntry{
-		Timestamp: timestamp,
-		Level:     level,
-		Message:   strings.TrimSpace(message),
-		Source:    source,
-		Raw:       line,
+		Timestamp:       timestamp,
+		TimestampStatus: status,
+		Level:           level,
+		LevelDefault:    !found,
+		Message:         message,
+		Source:          source,
+		Raw:             line,
+		Fields:          extractKeyValues(message),
 	}
 
 	return entry, nil
@@ -90,8 +107,9 @@ func extractTimestamp(line string) (time.Time, string, error) {
 	return time.Time{}, line, fmt.Errorf("no timestamp found")
 }
 
-// extractLevelAndMessage extracts log level and message
-func extractLevelAndMessage(line string) (models.LogLevel, string) {
+// extractLevelAndMessage extracts log level and message, and whether the
+// line named a level at all
+func extractLevelAndMessage(line string) (models.LogLevel, string, bool) {
 	line = strings.TrimSpace(line)
 
 	// Common patterns: "ERROR:", "ERROR -", "ERROR"
@@ -108,7 +126,7 @@ func extractLevelAndMessage(line string) (models.LogLevel, string) {
 		for _, pattern := range patterns {
 			if strings.HasPrefix(strings.ToUpper(line), pattern) {
 				message := strings.TrimSpace(line[len(pattern):])
-				return models.ParseLogLevel(lvl), message
+				return models.ParseLogLevel(lvl), message, true
 			}
 		}
 	}
@@ -116,10 +134,10 @@ func extractLevelAndMessage(line string) (models.LogLevel, string) {
 	// If no level found, check if line contains level keyword
 	for _, lvl := range levels {
 		if strings.Contains(strings.ToUpper(line), lvl) {
-			return models.ParseLogLevel(lvl), line
+			return models.ParseLogLevel(lvl), line, true
 		}
 	}
 
 	// Default to INFO if no level found
-	return models.INFO, line
+	return models.INFO, line, false
 }
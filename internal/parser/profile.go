@@ -0,0 +1,356 @@
+package parser
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aadithyaa9/loganalyzer/internal/models"
+)
+
+// EpochUnit is the unit of numeric timestamps (enum pattern)
+type EpochUnit int
+
+const (
+	EpochAuto EpochUnit = iota // Guess from magnitude
+	EpochSeconds
+	EpochMillis
+	EpochMicros
+	EpochNanos
+)
+
+func (u EpochUnit) String() string {
+	switch u {
+	case EpochAuto:
+		return "auto"
+	case EpochSeconds:
+		return "s"
+	case EpochMillis:
+		return "ms"
+	case EpochMicros:
+		return "us"
+	case EpochNanos:
+		return "ns"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseEpochUnit converts a unit name to EpochUnit
+func ParseEpochUnit(s string) (EpochUnit, error) {
+	switch strings.ToLower(s) {
+	case "", "auto":
+		return EpochAuto, nil
+	case "s", "sec", "seconds":
+		return EpochSeconds, nil
+	case "ms", "millis", "milliseconds":
+		return EpochMillis, nil
+	case "us", "µs", "micros", "microseconds":
+		return EpochMicros, nil
+	case "ns", "nanos", "nanoseconds":
+		return EpochNanos, nil
+	default:
+		return EpochAuto, fmt.Errorf("unknown epoch unit %q (s, ms, us, ns, auto)", s)
+	}
+}
+
+// LevelThreshold maps numeric levels from Min upward onto Level
+type LevelThreshold struct {
+	Min   float64
+	Level models.LogLevel
+}
+
+// JSONProfile tells JSONLogParser where a logging library puts the entry
+// attributes. Each attribute has candidate keys tried in order; a key may
+// be a dotted path into nested objects ("log.level"). Matched keys are
+// removed from the structured fields.
+type JSONProfile struct {
+	Name          string
+	Timestamp     []string
+	Level         []string
+	Message       []string
+	Service       []string         // Stored as the "service" field; the source stays the file
+	Source        []string         // Replaces the source (the file) when present; unset in the built-ins
+	Epoch         EpochUnit        // Unit of numeric timestamps
+	NumericLevels []LevelThreshold // Sorted by Min; empty means no numeric levels
+}
+
+// pinoLevels are the numeric levels used by pino and bunyan
+var pinoLevels = []LevelThreshold{
+	{10, models.DEBUG}, // trace
+	{20, models.DEBUG},
+	{30, models.INFO},
+	{40, models.WARN},
+	{50, models.ERROR},
+	{60, models.FATAL},
+}
+
+// Built-in profiles
+var (
+	// DefaultJSONProfile covers the common key names of most libraries
+	DefaultJSONProfile = &JSONProfile{
+		Name:          "default",
+		Timestamp:     []string{"timestamp", "time", "ts", "@timestamp"},
+		Level:         []string{"level", "lvl", "severity", "log.level"},
+		Message:       []string{"message", "msg"},
+		Epoch:         EpochAuto,
+		NumericLevels: pinoLevels,
+	}
+
+	jsonProfiles = map[string]*JSONProfile{
+		"default": DefaultJSONProfile,
+		"pino": {
+			Name:          "pino",
+			Timestamp:     []string{"time"},
+			Level:         []string{"level"},
+			Message:       []string{"msg"},
+			Service:       []string{"name"},
+			Epoch:         EpochMillis,
+			NumericLevels: pinoLevels,
+		},
+		"bunyan": {
+			Name:          "bunyan",
+			Timestamp:     []string{"time"},
+			Level:         []string{"level"},
+			Message:       []string{"msg"},
+			Service:       []string{"name"},
+			Epoch:         EpochMillis,
+			NumericLevels: pinoLevels,
+		},
+		"zap": {
+			Name:      "zap",
+			Timestamp: []string{"ts"},
+			Level:     []string{"level"},
+			Message:   []string{"msg"},
+			Service:   []string{"logger"},
+			Epoch:     EpochSeconds,
+		},
+		"ecs": {
+			Name:      "ecs",
+			Timestamp: []string{"@timestamp"},
+			Level:     []string{"log.level"},
+			Message:   []string{"message"},
+			Service:   []string{"service.name", "event.dataset"},
+		},
+		"gcp": {
+			Name:      "gcp",
+			Timestamp: []string{"timestamp", "time", "receiveTimestamp"},
+			Level:     []string{"severity"},
+			Message:   []string{"message", "textPayload", "jsonPayload.message"},
+			Service:   []string{"serviceContext.service", "logName"},
+		},
+	}
+)
+
+// JSONProfileNames returns the names of the built-in profiles
+func JSONProfileNames() []string {
+	names := make([]string, 0, len(jsonProfiles))
+	for name := range jsonProfiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// jsonProfileFile is the on-disk form of a user-defined profile
+type jsonProfileFile struct {
+	Name      string            `json:"name"`
+	Extends   string            `json:"extends"`
+	Timestamp []string          `json:"timestamp"`
+	Level     []string          `json:"level"`
+	Message   []string          `json:"message"`
+	Service   []string          `json:"service"`
+	Source    []string          `json:"source"`
+	Epoch     string            `json:"epoch"`
+	Levels    map[string]string `json:"levels"`
+}
+
+// LoadJSONProfile returns a built-in profile by name, or reads a
+// user-defined one from a JSON file such as:
+//
+//	{"extends": "pino", "message": ["event"], "service": ["app"], "epoch": "ms",
+//	 "levels": {"10": "DEBUG", "30": "INFO", "50": "ERROR"}}
+//
+// Omitted attributes are inherited from the extended profile (default if
+// none). "levels" maps the lower bound of each numeric range to a level.
+func LoadJSONProfile(nameOrPath string) (*JSONProfile, error) {
+	if profile, ok := jsonProfiles[strings.ToLower(nameOrPath)]; ok {
+		return profile, nil
+	}
+
+	data, err := os.ReadFile(nameOrPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("unknown JSON profile %q (built-in: %s, or a profile file)",
+				nameOrPath, strings.Join(JSONProfileNames(), ", "))
+		}
+		return nil, fmt.Errorf("failed to read JSON profile: %w", err)
+	}
+
+	var file jsonProfileFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("invalid JSON profile %s: %w", nameOrPath, err)
+	}
+
+	base := DefaultJSONProfile
+	if file.Extends != "" {
+		if base = jsonProfiles[strings.ToLower(file.Extends)]; base == nil {
+			return nil, fmt.Errorf("JSON profile %s extends unknown profile %q", nameOrPath, file.Extends)
+		}
+	}
+
+	profile := *base
+	profile.Name = file.Name
+	if profile.Name == "" {
+		profile.Name = nameOrPath
+	}
+	if file.Timestamp != nil {
+		profile.Timestamp = file.Timestamp
+	}
+	if file.Level != nil {
+		profile.Level = file.Level
+	}
+	if file.Message != nil {
+		profile.Message = file.Message
+	}
+	if file.Service != nil {
+		profile.Service = file.Service
+	}
+	if file.Source != nil {
+		profile.Source = file.Source
+	}
+	if file.Epoch != "" {
+		if profile.Epoch, err = ParseEpochUnit(file.Epoch); err != nil {
+			return nil, fmt.Errorf("invalid JSON profile %s: %w", nameOrPath, err)
+		}
+	}
+	if file.Levels != nil {
+		profile.NumericLevels = nil
+		for bound, name := range file.Levels {
+			min, err := strconv.ParseFloat(bound, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid JSON profile %s: level bound %q is not a number", nameOrPath, bound)
+			}
+			level := models.ParseLogLevel(strings.ToUpper(name))
+			if level == models.UNKNOWN {
+				return nil, fmt.Errorf("invalid JSON profile %s: unknown level %q", nameOrPath, name)
+			}
+			profile.NumericLevels = append(profile.NumericLevels, LevelThreshold{min, level})
+		}
+		sort.Slice(profile.NumericLevels, func(i, j int) bool {
+			return profile.NumericLevels[i].Min < profile.NumericLevels[j].Min
+		})
+	}
+
+	return &profile, nil
+}
+
+// takePath removes the first present candidate from object and returns its
+// value. A candidate matches a literal key first, then a nested path.
+func takePath(object map[string]any, candidates []string) (any, bool) {
+	for _, path := range candidates {
+		if value, ok := removePath(object, path); ok && value != nil && value != "" {
+			return value, true
+		}
+	}
+	return nil, false
+}
+
+// removePath deletes a literal or dotted key, pruning emptied parents
+func removePath(object map[string]any, path string) (any, bool) {
+	if value, ok := object[path]; ok {
+		delete(object, path)
+		return value, true
+	}
+
+	head, rest, ok := strings.Cut(path, ".")
+	if !ok {
+		return nil, false
+	}
+	nested, isMap := object[head].(map[string]any)
+	if !isMap {
+		return nil, false
+	}
+	value, found := removePath(nested, rest)
+	if found && len(nested) == 0 {
+		delete(object, head)
+	}
+	return value, found
+}
+
+// timestamp converts a timestamp value: a string in any supported layout,
+// or an epoch number (also as a string) in the profile's unit
+func (p *JSONProfile) timestamp(value any) (time.Time, error) {
+	s := models.FormatFieldValue(value)
+	if t, ok := epochTime(s, p.Epoch); ok {
+		return t, nil
+	}
+	return parseTimestamp(s)
+}
+
+// epochTime converts an epoch number. EpochAuto picks the unit from the
+// magnitude: seconds up to the year ~5000, then ms, µs and ns. Integers
+// are converted exactly; fractions (zap's float seconds) via float64.
+func epochTime(s string, unit EpochUnit) (time.Time, bool) {
+	number, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	if unit == EpochAuto {
+		switch abs := math.Abs(number); {
+		case abs < 1e11:
+			unit = EpochSeconds
+		case abs < 1e14:
+			unit = EpochMillis
+		case abs < 1e17:
+			unit = EpochMicros
+		default:
+			unit = EpochNanos
+		}
+	}
+
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		switch unit {
+		case EpochMillis:
+			return time.UnixMilli(n).UTC(), true
+		case EpochMicros:
+			return time.UnixMicro(n).UTC(), true
+		case EpochNanos:
+			return time.Unix(0, n).UTC(), true
+		default:
+			return time.Unix(n, 0).UTC(), true
+		}
+	}
+
+	switch unit {
+	case EpochMillis:
+		number *= 1e6
+	case EpochMicros:
+		number *= 1e3
+	case EpochNanos:
+	default:
+		number *= 1e9
+	}
+	return time.Unix(0, int64(number)).UTC(), true
+}
+
+// level converts a level value, mapping numbers through NumericLevels
+func (p *JSONProfile) level(value any) models.LogLevel {
+	s := models.FormatFieldValue(value)
+	if number, err := strconv.ParseFloat(s, 64); err == nil {
+		level := models.UNKNOWN
+		for _, threshold := range p.NumericLevels {
+			if number >= threshold.Min {
+				level = threshold.Level
+			}
+		}
+		return level
+	}
+	return models.ParseLogLevel(strings.ToUpper(s))
+}
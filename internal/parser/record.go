@@ -0,0 +1,119 @@
+package parser
+
+import "github.com/aadithyaa9/loganalyzer/internal/models"
+
+// Record is one log entry's text as it goes to LogParser.Parse, with the
+// container envelope (nil outside containers) and position of its first line
+type Record struct {
+	Text     string
+	Envelope *ContainerEnvelope
+	Position models.Position
+}
+
+// Apply records the envelope and position on an entry parsed from the record
+func (r Record) Apply(entry *models.LogEntry) {
+	r.Envelope.Apply(entry)
+	entry.Position = r.Position
+}
+
+// RecordAssembler turns raw lines into records: it unwraps container
+// envelopes, then folds multiline entries when a MultilineAssembler is set
+type RecordAssembler struct {
+	containers *ContainerUnwrapper
+	multiline  *MultilineAssembler
+
+	first Record // First line of the entry buffered in multiline, without text
+}
+
+// NewRecordAssembler creates an assembler; multiline may be nil
+func NewRecordAssembler(multiline *MultilineAssembler) *RecordAssembler {
+	return &RecordAssembler{
+		containers: NewContainerUnwrapper(),
+		multiline:  multiline,
+	}
+}
+
+// Add feeds one raw line found at pos and returns a record when one is complete
+func (r *RecordAssembler) Add(line string, pos models.Position) (Record, bool) {
+	unwrapped, ok := r.containers.Unwrap(line, pos)
+	if !ok {
+		return Record{}, false
+	}
+	return r.add(unwrapped)
+}
+
+// Flush returns the buffered records; call it until it returns false
+func (r *RecordAssembler) Flush() (Record, bool) {
+	for {
+		unwrapped, ok := r.containers.Flush()
+		if !ok {
+			break
+		}
+		if record, ok := r.add(unwrapped); ok {
+			return record, true
+		}
+	}
+
+	if r.multiline == nil {
+		return Record{}, false
+	}
+	text, ok := r.multiline.Flush()
+	if !ok {
+		return Record{}, false
+	}
+	record := r.first
+	record.Text = text
+	return record, true
+}
+
+// Pending reports whether a partial line or multiline entry is buffered
+func (r *RecordAssembler) Pending() bool {
+	return len(r.containers.order) > 0 || (r.multiline != nil && r.multiline.Pending())
+}
+
+// PendingStart returns where the earliest buffered line starts, so that
+// reading resumed there loses nothing; false when nothing is buffered
+func (r *RecordAssembler) PendingStart() (models.Position, bool) {
+	var start models.Position
+	found := false
+	consider := func(pos models.Position) {
+		if !found || pos.Offset < start.Offset {
+			start, found = pos, true
+		}
+	}
+	for _, stream := range r.containers.order {
+		consider(r.containers.partial[stream].position)
+	}
+	if r.multiline != nil && r.multiline.Pending() {
+		consider(r.first.Position)
+	}
+	return start, found
+}
+
+// Format returns the detected container envelope format
+func (r *RecordAssembler) Format() ContainerFormat {
+	return r.containers.Format()
+}
+
+// add passes an unwrapped line through multiline assembly
+func (r *RecordAssembler) add(line Record) (Record, bool) {
+	if r.multiline == nil {
+		if line.Text == "" {
+			return Record{}, false
+		}
+		return line, true
+	}
+
+	wasPending := r.multiline.Pending()
+	previous := r.first
+	completed, ok := r.multiline.Add(line.Text)
+	if ok || (!wasPending && r.multiline.Pending()) {
+		// The line began a new entry
+		r.first = Record{Envelope: line.Envelope, Position: line.Position}
+	}
+	if !ok {
+		return Record{}, false
+	}
+	previous.Text = completed
+	return previous, true
+}
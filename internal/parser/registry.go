@@ -0,0 +1,114 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ErrNotConfigured is returned by factories of parsers that need options
+// (such as a grok expression) which were not given
+var ErrNotConfigured = errors.New("parser not configured")
+
+// Options configures the parsers created by the registry
+type Options struct {
+	JSONProfile    *JSONProfile      // Key mapping for JSON (nil for the default)
+	LogfmtKeys     KeyAliases        // Key aliases for logfmt (zero value for the defaults)
+	GrokExpression string            // Expression for the grok parser
+	GrokPatterns   map[string]string // Extra grok pattern definitions
+}
+
+// Registration describes a parser known to the registry
+type Registration struct {
+	Name string
+
+	// Confidence is how specific a CanParse match is, from 0 to 1. A line
+	// that starts with "{" and decodes is almost surely JSON; plain text
+	// accepts anything and so has a low confidence.
+	Confidence float64
+
+	// Fallback marks the parser used when nothing more specific matches
+	Fallback bool
+
+	// New creates the parser; ErrNotConfigured leaves it out of detection
+	New func(opts Options) (LogParser, error)
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]Registration)
+)
+
+// Register adds a parser to the registry. Parsers register themselves from
+// init functions; registering a name twice panics.
+func Register(r Registration) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	name := strings.ToLower(r.Name)
+	if _, exists := registry[name]; exists {
+		panic(fmt.Sprintf("parser: %q registered twice", name))
+	}
+	registry[name] = r
+}
+
+// Names returns the registered parser names, sorted
+func Names() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// New creates the registered parser with the given name
+func New(name string, opts Options) (LogParser, error) {
+	registryMu.RLock()
+	r, ok := registry[strings.ToLower(name)]
+	registryMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown parser %q (%s)", name, strings.Join(Names(), ", "))
+	}
+
+	p, err := r.New(opts)
+	if err != nil {
+		return nil, fmt.Errorf("%s parser: %w", r.Name, err)
+	}
+	return p, nil
+}
+
+// candidate is an instantiated parser taking part in detection
+type candidate struct {
+	parser     LogParser
+	confidence float64
+	fallback   bool
+}
+
+// candidates instantiates every configured parser, most confident first
+func candidates(opts Options) []candidate {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	var result []candidate
+	for _, r := range registry {
+		p, err := r.New(opts)
+		if err != nil {
+			continue // e.g. grok without an expression
+		}
+		result = append(result, candidate{parser: p, confidence: r.Confidence, fallback: r.Fallback})
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].confidence != result[j].confidence {
+			return result[i].confidence > result[j].confidence
+		}
+		return result[i].parser.Name() < result[j].parser.Name()
+	})
+	return result
+}
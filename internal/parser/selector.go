@@ -0,0 +1,177 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aadithyaa9/loganalyzer/internal/models"
+)
+
+// DefaultSampleLines is how many lines a Selector samples before locking
+// onto a parser
+const DefaultSampleLines = 20
+
+// Selector chooses the parser for one source. It detects per line while
+// sampling the first lines, then locks onto the parser that matched most
+// of them, weighted by confidence, so later lines skip detection. Lines the
+// locked parser rejects are detected individually, and when most lines of
+// a window are rejected the selector goes back to sampling.
+type Selector struct {
+	detector   *Detector
+	sampleSize int
+	fixed      bool // Forced parser: never re-sampled, no fallback
+
+	hits    []int // CanParse matches per detector candidate while sampling
+	sampled int
+
+	locked  LogParser
+	checked int // Lines parsed since the current window started
+	failed  int // Of those, lines the locked parser rejected
+
+	// Why the locked parser rejected the last line, when only the
+	// plain-text fallback accepted it
+	mismatch error
+
+	counts map[string]int // Entries parsed, by parser name
+}
+
+// NewSelector creates a selector that samples sampleSize lines per lock
+func (d *Detector) NewSelector(sampleSize int) *Selector {
+	if sampleSize <= 0 {
+		sampleSize = DefaultSampleLines
+	}
+	return &Selector{
+		detector:   d,
+		sampleSize: sampleSize,
+		hits:       make([]int, len(d.candidates)),
+		counts:     make(map[string]int),
+	}
+}
+
+// NewFixedSelector creates a selector that always uses p
+func NewFixedSelector(p LogParser) *Selector {
+	return &Selector{
+		fixed:  true,
+		locked: p,
+		counts: make(map[string]int),
+	}
+}
+
+// Parse parses a line with the current parser
+func (s *Selector) Parse(line string, source string) (*models.LogEntry, error) {
+	s.mismatch = nil
+	if s.locked == nil {
+		return s.parseWith(s.sample(line), line, source)
+	}
+	if s.fixed {
+		return s.parseWith(s.locked, line, source)
+	}
+
+	// Lenient parsers (logfmt, plain) accept almost any line, so the locked
+	// parser must still recognize the line for it to count as a match
+	var entry *models.LogEntry
+	err := ErrInvalidFormat
+	if s.locked.CanParse(strings.TrimSpace(line)) {
+		entry, err = s.locked.Parse(line, source)
+	}
+	s.checked++
+	if err == nil {
+		s.counts[s.locked.Name()]++
+	} else {
+		s.failed++
+		rejected := fmt.Errorf("%s parser: %w", s.locked.Name(), err)
+		p := s.detector.Detect(line)
+		entry, err = s.parseWith(p, line, source)
+		if err == nil && p == s.detector.fallback {
+			s.mismatch = rejected
+		}
+	}
+
+	if s.checked >= s.sampleSize {
+		if s.failed*2 > s.checked {
+			s.unlock()
+		}
+		s.checked, s.failed = 0, 0
+	}
+	return entry, err
+}
+
+// Locked returns the parser the selector has locked onto, or nil while sampling
+func (s *Selector) Locked() LogParser {
+	return s.locked
+}
+
+// Mismatch returns why the parser the selector has locked onto rejected
+// the line of the last Parse call, when only the plain-text fallback
+// accepted it; nil otherwise. Such a line parsed, but most likely wrong.
+func (s *Selector) Mismatch() error {
+	return s.mismatch
+}
+
+// Counts returns how many entries each parser produced
+func (s *Selector) Counts() map[string]int {
+	return s.counts
+}
+
+// sample tallies which candidates accept the line, locks once enough lines
+// were seen, and returns the parser for this line
+func (s *Selector) sample(line string) LogParser {
+	trimmed := strings.TrimSpace(line)
+	var best LogParser
+	for i, c := range s.detector.candidates {
+		if c.parser.CanParse(trimmed) {
+			s.hits[i]++
+			if best == nil {
+				best = c.parser
+			}
+		}
+	}
+	if best == nil {
+		best = s.detector.fallback
+	}
+
+	s.sampled++
+	if s.sampled >= s.sampleSize {
+		s.lock()
+	}
+	return best
+}
+
+// lock picks the candidate with the best confidence-weighted share of the
+// sample. Only a parser that matched most sampled lines is locked in;
+// otherwise (mixed or unknown formats) sampling starts over.
+func (s *Selector) lock() {
+	bestScore := 0.0
+	for i, c := range s.detector.candidates {
+		if s.hits[i]*2 <= s.sampled {
+			continue
+		}
+		if score := c.confidence * float64(s.hits[i]) / float64(s.sampled); score > bestScore {
+			bestScore = score
+			s.locked = c.parser
+		}
+	}
+	s.resetSample()
+}
+
+// unlock returns to sampling after the locked parser stopped matching
+func (s *Selector) unlock() {
+	s.locked = nil
+	s.resetSample()
+}
+
+func (s *Selector) resetSample() {
+	for i := range s.hits {
+		s.hits[i] = 0
+	}
+	s.sampled = 0
+}
+
+// parseWith parses with p and counts the entry
+func (s *Selector) parseWith(p LogParser, line, source string) (*models.LogEntry, error) {
+	entry, err := p.Parse(line, source)
+	if err == nil {
+		s.counts[p.Name()]++
+	}
+	return entry, err
+}
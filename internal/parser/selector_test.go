@@ -0,0 +1,201 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/aadithyaa9/loganalyzer/internal/models"
+)
+
+func TestPlainTextLevel(t *testing.T) {
+	tests := []struct {
+		line         string
+		level        models.LogLevel
+		levelDefault bool
+	}{
+		{"2024-01-15 10:00:00 ERROR: disk full", models.ERROR, false},
+		{"[2024-01-15 10:00:00] WARN - slow", models.WARN, false},
+		{"2024-01-15 10:00:00 request failed with FATAL signal", models.FATAL, false},
+		{"2024-01-15 10:00:00 server started", models.INFO, true},
+		{"no timestamp and no level", models.INFO, true},
+	}
+
+	p := &PlainTextLogParser{}
+	for _, tt := range tests {
+		entry, err := p.Parse(tt.line, "app.log")
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.line, err)
+			continue
+		}
+		if entry.Level != tt.level || entry.LevelDefault != tt.levelDefault {
+			t.Errorf("Parse(%q) level = %s (default %v), want %s (default %v)",
+				tt.line, entry.Level, entry.LevelDefault, tt.level, tt.levelDefault)
+		}
+	}
+}
+
+func TestSelectorMismatch(t *testing.T) {
+	s := NewDetector(Options{}).NewSelector(5)
+	for i := 0; i < 5; i++ {
+		if _, err := s.Parse(fmt.Sprintf(`{"level":"info","msg":"m%d"}`, i), "app.log"); err != nil {
+			t.Fatalf("sample %d: %v", i, err)
+		}
+		if s.Mismatch() != nil {
+			t.Errorf("sample %d: Mismatch() = %v while sampling, want nil", i, s.Mismatch())
+		}
+	}
+	if locked := s.Locked(); locked == nil || locked.Name() != "JSON" {
+		t.Fatalf("Locked() = %v, want JSON", locked)
+	}
+
+	tests := []struct {
+		line     string
+		parser   string
+		mismatch bool
+	}{
+		{`{"level":"error","msg":"boom"}`, "JSON", false},
+		{"Starting server on port 8080", "PlainText", true},
+		{"ts=2024-01-15T10:00:00Z level=warn msg=slow", "Logfmt", false},
+		{`{"level":"info","msg":"ok"}`, "JSON", false},
+	}
+	for _, tt := range tests {
+		before := s.Counts()[tt.parser]
+		entry, err := s.Parse(tt.line, "app.log")
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", tt.line, err)
+		}
+		if entry == nil || s.Counts()[tt.parser] != before+1 {
+			t.Errorf("Parse(%q) not counted for %s: %v", tt.line, tt.parser, s.Counts())
+		}
+		if got := s.Mismatch() != nil; got != tt.mismatch {
+			t.Errorf("Parse(%q) Mismatch() = %v, want mismatch %v", tt.line, s.Mismatch(), tt.mismatch)
+		}
+	}
+}
+
+func TestFixedSelectorReportsNoMismatch(t *testing.T) {
+	s := NewFixedSelector(&JSONLogParser{})
+	if _, err := s.Parse("not json", "app.log"); err == nil {
+		t.Errorf("Parse succeeded, want an error from the forced parser")
+	}
+	if s.Mismatch() != nil {
+		t.Errorf("Mismatch() = %v, want nil", s.Mismatch())
+	}
+}
+
+// jsonLines and logfmtLines return n lines of one format
+func jsonLines(n int) []string {
+	var lines []string
+	for i := 0; i < n; i++ {
+		lines = append(lines, fmt.Sprintf(`{"level":"info","msg":"m%d"}`, i))
+	}
+	return lines
+}
+
+func logfmtLines(n int) []string {
+	var lines []string
+	for i := 0; i < n; i++ {
+		lines = append(lines, fmt.Sprintf("ts=2024-01-15T10:00:00Z level=warn msg=m%d", i))
+	}
+	return lines
+}
+
+// lockedName returns the name of the parser s has locked onto, or "" while sampling
+func lockedName(s *Selector) string {
+	if p := s.Locked(); p != nil {
+		return p.Name()
+	}
+	return ""
+}
+
+func TestSelectorLocking(t *testing.T) {
+	tests := []struct {
+		name   string
+		lines  []string
+		locked []string // Locked parser after each line
+	}{
+		{
+			name:   "locks after the sample",
+			lines:  jsonLines(5),
+			locked: []string{"", "", "", "JSON", "JSON"},
+		},
+		{
+			name:   "no majority keeps sampling",
+			lines:  append(append(jsonLines(2), logfmtLines(2)...), logfmtLines(4)...),
+			locked: []string{"", "", "", "", "", "", "", "Logfmt"},
+		},
+		{
+			name:   "half a window of foreign lines keeps the lock",
+			lines:  append(append(jsonLines(4), logfmtLines(2)...), jsonLines(4)...),
+			locked: []string{"", "", "", "JSON", "JSON", "JSON", "JSON", "JSON", "JSON", "JSON"},
+		},
+		{
+			name:   "unlocks when most of a window mismatch",
+			lines:  append(jsonLines(4), logfmtLines(5)...),
+			locked: []string{"", "", "", "JSON", "JSON", "JSON", "JSON", "", ""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewDetector(Options{}).NewSelector(4)
+			for i, line := range tt.lines {
+				if _, err := s.Parse(line, "app.log"); err != nil {
+					t.Fatalf("line %d: %v", i+1, err)
+				}
+				if got := lockedName(s); got != tt.locked[i] {
+					t.Errorf("after line %d Locked() = %q, want %q", i+1, got, tt.locked[i])
+				}
+			}
+		})
+	}
+}
+
+func TestSelectorFormatSwitch(t *testing.T) {
+	// A file that starts as JSON and continues as logfmt, as after an
+	// application upgrade: every line is parsed by its own format, and the
+	// selector ends up locked onto the new one
+	lines := append(jsonLines(6), logfmtLines(10)...)
+	s := NewDetector(Options{}).NewSelector(4)
+	for i, line := range lines {
+		entry, err := s.Parse(line, "app.log")
+		if err != nil {
+			t.Fatalf("line %d: %v", i+1, err)
+		}
+		if want := fmt.Sprintf("m%d", i%6); i < 6 && entry.Message != want {
+			t.Errorf("line %d message = %q, want %q", i+1, entry.Message, want)
+		}
+		if s.Mismatch() != nil {
+			t.Errorf("line %d Mismatch() = %v, want nil", i+1, s.Mismatch())
+		}
+	}
+	if got := lockedName(s); got != "Logfmt" {
+		t.Errorf("Locked() = %q, want Logfmt", got)
+	}
+	if counts := s.Counts(); counts["JSON"] != 6 || counts["Logfmt"] != 10 || len(counts) != 2 {
+		t.Errorf("Counts() = %v, want 6 JSON and 10 Logfmt", counts)
+	}
+}
+
+func TestSelectorParserOverride(t *testing.T) {
+	// --parser logfmt: every line goes to logfmt, however many are JSON
+	p, err := New("logfmt", Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := NewFixedSelector(p)
+	for i, line := range append(logfmtLines(2), jsonLines(10)...) {
+		s.Parse(line, "app.log")
+		if got := lockedName(s); got != "Logfmt" {
+			t.Fatalf("after line %d Locked() = %q, want Logfmt", i+1, got)
+		}
+	}
+	if counts := s.Counts(); counts["JSON"] != 0 || counts["Logfmt"] < 2 {
+		t.Errorf("Counts() = %v, want only Logfmt", counts)
+	}
+
+	if _, err := New("bogus", Options{}); err == nil || !strings.Contains(err.Error(), `unknown parser "bogus"`) {
+		t.Errorf("New(bogus) error = %v, want an unknown parser error", err)
+	}
+}
@@ -0,0 +1,324 @@
+package parser
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aadithyaa9/loganalyzer/internal/models"
+)
+
+func init() {
+	Register(Registration{
+		Name:       "syslog",
+		Confidence: 0.85,
+		New: func(opts Options) (LogParser, error) {
+			return &SyslogLogParser{}, nil
+		},
+	})
+}
+
+// Syslog line layouts
+var (
+	// syslog5424Line matches: <PRI>VERSION TIMESTAMP HOSTNAME APP-NAME PROCID MSGID SD-AND-MSG
+	syslog5424Line = regexp.MustCompile(`^<(\d{1,3})>(\d{1,2}) (\S+) (\S+) (\S+) (\S+) (\S+) ?(.*)$`)
+
+	// syslog3164Line matches an optional <PRI>, a BSD "Oct 16 10:22:01" or
+	// rsyslog RFC 3339 timestamp, then the rest of the line
+	syslog3164Line = regexp.MustCompile(`^(?:<(\d{1,3})>)?(?:([A-Z][a-z]{2} [ \d]\d \d{2}:\d{2}:\d{2}(?:\.\d+)?)|` +
+		`(\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(?:\.\d+)?(?:Z|[+-]\d{2}:\d{2}))) (.*)$`)
+
+	// syslogTag matches the "app[pid]: " tag in front of a BSD message
+	syslogTag = regexp.MustCompile(`^([^\s:\[\]]+)(?:\[([^\]]*)\])?: ?`)
+)
+
+// syslogBSDFormats are the RFC 3164 timestamp layouts (no year, no zone)
+var syslogBSDFormats = []string{"Jan _2 15:04:05", "Jan _2 15:04:05.999999999"}
+
+// syslogFacilities are the facility names by code (RFC 5424 section 6.2.1)
+var syslogFacilities = []string{
+	"kern", "user", "mail", "daemon", "auth", "syslog", "lpr", "news",
+	"uucp", "cron", "authpriv", "ftp", "ntp", "security", "console", "solaris-cron",
+	"local0", "local1", "local2", "local3", "local4", "local5", "local6", "local7",
+}
+
+// syslogSeverities are the severity names by code
+var syslogSeverities = []string{"emerg", "alert", "crit", "err", "warning", "notice", "info", "debug"}
+
+// SyslogLogParser parses RFC 3164 (BSD) and RFC 5424 syslog lines, with or
+// without the <PRI> header that files written by syslog daemons omit
+type SyslogLogParser struct {
+	// Location for BSD timestamps, which carry no zone (UTC if nil)
+	Location *time.Location
+}
+
+// Parse parses a syslog line
+// Expected formats:
+// <34>Oct 16 10:22:01 host sshd[812]: Failed password for root
+// Oct 16 10:22:01 host sshd[812]: Failed password for root
+// 2026-10-16T10:22:01.123456+00:00 host sshd[812]: Failed password for root
+// <165>1 2026-10-16T10:22:01.003Z host app 812 ID47 [exampleSDID@32473 iut="3"] message
+func (p *SyslogLogParser) Parse(line string, source string) (*models.LogEntry, error) {
+	if strings.TrimSpace(line) == "" {
+		return nil, ErrEmptyLine
+	}
+
+	if m := syslog5424Line.FindStringSubmatch(line); m != nil {
+		return p.parse5424(m, line, source)
+	}
+	if m := syslog3164Line.FindStringSubmatch(line); m != nil {
+		return p.parse3164(m, line, source)
+	}
+	return nil, fmt.Errorf("%w: not a syslog line", ErrInvalidFormat)
+}
+
+// CanParse checks if a line looks like syslog
+func (p *SyslogLogParser) CanParse(line string) bool {
+	if line == "" {
+		return false
+	}
+	if line[0] == '<' {
+		return syslog5424Line.MatchString(line) || syslog3164Line.MatchString(line)
+	}
+
+	m := syslog3164Line.FindStringSubmatch(line)
+	if m == nil {
+		return false
+	}
+	// A BSD timestamp is distinctive on its own; an RFC 3339 one also
+	// starts other formats, so require the "host app[pid]:" header too
+	if m[2] != "" {
+		return true
+	}
+	_, rest, ok := strings.Cut(m[4], " ")
+	return ok && !syslogTag.MatchString(m[4]) && syslogTag.MatchString(rest)
+}
+
+// Name returns the parser name
+func (p *SyslogLogParser) Name() string {
+	return "Syslog"
+}
+
+// parse5424 builds an entry from an RFC 5424 line
+func (p *SyslogLogParser) parse5424(m []string, line, source string) (*models.LogEntry, error) {
+	fields := make(models.Fields)
+	level, ok := setPriority(fields, m[1])
+	if !ok {
+		return nil, fmt.Errorf("%w: bad syslog priority %q", ErrInvalidFormat, m[1])
+	}
+
+	status := models.TimestampParsed
+	timestamp, err := time.Parse(time.RFC3339Nano, m[3])
+	if err != nil {
+		if m[3] != "-" {
+			return nil, fmt.Errorf("%w: bad syslog timestamp %q", ErrInvalidFormat, m[3])
+		}
+		timestamp = time.Now() // NILVALUE timestamp
+		status = models.TimestampMissing
+	}
+
+	setUnlessDash(fields, "hostname", m[4])
+	setUnlessDash(fields, "app", m[5])
+	if m[6] != "-" {
+		fields.SetInferred("pid", m[6])
+	}
+	setUnlessDash(fields, "msgid", m[7])
+
+	data, message, err := parseStructuredData(m[8])
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > 0 {
+		fields.Set("sd", data)
+	}
+
+	return &models.LogEntry{
+		Timestamp:       timestamp,
+		TimestampStatus: status,
+		Level:           level,
+		Message:         strings.TrimPrefix(message, "\uFEFF"),
+		Source:          source,
+		Raw:             line,
+		Fields:          fields,
+	}, nil
+}
+
+// parse3164 builds an entry from a BSD-style line
+func (p *SyslogLogParser) parse3164(m []string, line, source string) (*models.LogEntry, error) {
+	fields := make(models.Fields)
+
+	var timestamp time.Time
+	if m[2] != "" {
+		t, err := p.parseBSDTimestamp(m[2], time.Now())
+		if err != nil {
+			return nil, err
+		}
+		timestamp = t
+	} else {
+		t, err := time.Parse(time.RFC3339Nano, m[3])
+		if err != nil {
+			return nil, fmt.Errorf("%w: bad syslog timestamp %q", ErrInvalidFormat, m[3])
+		}
+		timestamp = t
+	}
+
+	// HOSTNAME, unless the sender left it out and the tag follows directly
+	rest := m[4]
+	if host, after, ok := strings.Cut(rest, " "); ok && !syslogTag.MatchString(rest) {
+		fields["hostname"] = host
+		rest = after
+	}
+
+	if tag := syslogTag.FindStringSubmatch(rest); tag != nil {
+		fields["app"] = tag[1]
+		if tag[2] != "" {
+			fields.SetInferred("pid", tag[2])
+		}
+		rest = rest[len(tag[0]):]
+	}
+
+	// Daemons writing files drop the PRI; fall back to the message text,
+	// and to INFO, the severity of most such lines, if it names no level
+	level, ok := setPriority(fields, m[1])
+	levelDefault := false
+	if m[1] == "" {
+		var found bool
+		level, _, found = extractLevelAndMessage(rest)
+		levelDefault = !found
+	} else if !ok {
+		return nil, fmt.Errorf("%w: bad syslog priority %q", ErrInvalidFormat, m[1])
+	}
+
+	return &models.LogEntry{
+		Timestamp:    timestamp,
+		Level:        level,
+		LevelDefault: levelDefault,
+		Message:      strings.TrimSpace(rest),
+		Source:       source,
+		Raw:          line,
+		Fields:       fields,
+	}, nil
+}
+
+// parseBSDTimestamp parses a yearless timestamp, inferring the year as the
+// latest one that does not put the entry in the future relative to now.
+// A December entry read in January therefore lands in the previous year.
+func (p *SyslogLogParser) parseBSDTimestamp(s string, now time.Time) (time.Time, error) {
+	loc := p.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	for _, format := range syslogBSDFormats {
+		t, err := time.ParseInLocation(format, s, loc)
+		if err != nil {
+			continue
+		}
+
+		withYear := func(year int) time.Time {
+			return time.Date(year, t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), loc)
+		}
+
+		// Allow a day of clock skew between the sender and this machine
+		candidate := withYear(now.In(loc).Year())
+		if candidate.After(now.Add(24 * time.Hour)) {
+			candidate = withYear(candidate.Year() - 1)
+		}
+		return candidate, nil
+	}
+	return time.Time{}, fmt.Errorf("%w: bad syslog timestamp %q", ErrInvalidFormat, s)
+}
+
+// setPriority decodes <PRI> into facility and severity fields and returns
+// the matching level. An empty pri is not an error but sets nothing.
+func setPriority(fields models.Fields, pri string) (models.LogLevel, bool) {
+	if pri == "" {
+		return models.INFO, false
+	}
+	code, err := strconv.Atoi(pri)
+	if err != nil || code > 191 {
+		return models.UNKNOWN, false
+	}
+
+	facility, severity := code/8, code%8
+	fields["facility"] = syslogFacilities[facility]
+	fields["severity"] = syslogSeverities[severity]
+	return levelFromSeverity(severity), true
+}
+
+// levelFromSeverity maps the 8 syslog severities onto log levels
+func levelFromSeverity(severity int) models.LogLevel {
+	switch severity {
+	case 0, 1, 2: // emerg, alert, crit
+		return models.FATAL
+	case 3: // err
+		return models.ERROR
+	case 4: // warning
+		return models.WARN
+	case 5, 6: // notice, info
+		return models.INFO
+	default: // debug
+		return models.DEBUG
+	}
+}
+
+// parseStructuredData splits RFC 5424 STRUCTURED-DATA from the message.
+// Each [id name="value" ...] element becomes a map of its parameters.
+func parseStructuredData(s string) (map[string]any, string, error) {
+	if s == "-" || strings.HasPrefix(s, "- ") {
+		return nil, strings.TrimPrefix(s[1:], " "), nil
+	}
+	if !strings.HasPrefix(s, "[") {
+		return nil, "", fmt.Errorf("%w: missing syslog structured data", ErrInvalidFormat)
+	}
+
+	data := make(map[string]any)
+	i := 0
+	for i < len(s) && s[i] == '[' {
+		i++
+		start := i
+		for i < len(s) && s[i] != ' ' && s[i] != ']' {
+			i++
+		}
+		id := s[start:i]
+		params := make(map[string]any)
+
+		for i < len(s) && s[i] == ' ' {
+			i++
+			start = i
+			for i < len(s) && s[i] != '=' {
+				i++
+			}
+			name := s[start:i]
+			if i+1 >= len(s) || s[i+1] != '"' {
+				return nil, "", fmt.Errorf("%w: bad syslog structured data in %q", ErrInvalidFormat, id)
+			}
+			i += 2
+
+			// PARAM-VALUE escapes ", \ and ] with a backslash
+			var value strings.Builder
+			for i < len(s) && s[i] != '"' {
+				if s[i] == '\\' && i+1 < len(s) && strings.IndexByte(`"\]`, s[i+1]) >= 0 {
+					i++
+				}
+				value.WriteByte(s[i])
+				i++
+			}
+			if i >= len(s) {
+				return nil, "", fmt.Errorf("%w: unterminated syslog structured data in %q", ErrInvalidFormat, id)
+			}
+			i++
+			params[name] = value.String()
+		}
+
+		if i >= len(s) || s[i] != ']' {
+			return nil, "", fmt.Errorf("%w: unterminated syslog structured data in %q", ErrInvalidFormat, id)
+		}
+		i++
+		data[id] = params
+	}
+
+	return data, strings.TrimPrefix(s[i:], " "), nil
+}
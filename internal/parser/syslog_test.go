@@ -0,0 +1,153 @@
+package parser
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aadithyaa9/loganalyzer/internal/models"
+)
+
+func TestSyslogLogParser(t *testing.T) {
+	tests := []struct {
+		name    string
+		line    string
+		level   models.LogLevel
+		message string
+		fields  map[string]any
+		absent  []string
+		wantErr bool
+	}{
+		{
+			name:    "RFC 3164 with priority",
+			line:    "<34>Oct 16 10:22:01 host sshd[812]: Failed password for root",
+			level:   models.FATAL,
+			message: "Failed password for root",
+			fields:  map[string]any{"facility": "auth", "severity": "crit", "hostname": "host", "app": "sshd", "pid": 812.0},
+		},
+		{
+			name:    "RFC 3164 as written to a file",
+			line:    "Oct  6 10:22:01 host cron[77]: error: job failed",
+			level:   models.ERROR,
+			message: "error: job failed",
+			fields:  map[string]any{"hostname": "host", "app": "cron", "pid": 77.0},
+			absent:  []string{"facility", "severity"},
+		},
+		{
+			name:    "RFC 3164 without a hostname",
+			line:    "<13>Oct 16 10:22:01 kernel: usb 1-1: new device",
+			level:   models.INFO,
+			message: "usb 1-1: new device",
+			fields:  map[string]any{"app": "kernel", "facility": "user", "severity": "notice"},
+			absent:  []string{"hostname", "pid"},
+		},
+		{
+			name:    "rsyslog RFC 3339 timestamp",
+			line:    "2026-10-16T10:22:01.123456+00:00 host nginx[9]: upstream timed out",
+			level:   models.INFO,
+			message: "upstream timed out",
+			fields:  map[string]any{"hostname": "host", "app": "nginx", "pid": 9.0},
+		},
+		{
+			name:    "RFC 5424",
+			line:    `<165>1 2026-10-16T10:22:01.003Z host app 812 ID47 [exampleSDID@32473 iut="3" eventSource="App\"lication"] started`,
+			level:   models.INFO,
+			message: "started",
+			fields: map[string]any{
+				"facility": "local4", "severity": "notice", "hostname": "host", "app": "app",
+				"pid": 812.0, "msgid": "ID47",
+				"sd.exampleSDID@32473.iut":         "3",
+				"sd.exampleSDID@32473.eventSource": `App"lication`,
+			},
+		},
+		{
+			name:    "RFC 5424 with nil values",
+			line:    "<11>1 - host - - - - disk failing",
+			level:   models.ERROR,
+			message: "disk failing",
+			fields:  map[string]any{"hostname": "host"},
+			absent:  []string{"app", "pid", "msgid", "sd"},
+		},
+		{name: "priority out of range", line: "<192>Oct 16 10:22:01 host app: x", wantErr: true},
+		{name: "RFC 5424 without structured data", line: "<165>1 2026-10-16T10:22:01Z host app 1 ID message", wantErr: true},
+		{name: "not syslog", line: "just some text", wantErr: true},
+	}
+
+	p := &SyslogLogParser{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry, err := p.Parse(tt.line, "syslog")
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Parse(%q) succeeded, want an error", tt.line)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.line, err)
+			}
+			if entry.Level != tt.level {
+				t.Errorf("level = %s, want %s", entry.Level, tt.level)
+			}
+			if entry.Message != tt.message {
+				t.Errorf("message = %q, want %q", entry.Message, tt.message)
+			}
+			for key, want := range tt.fields {
+				if got, ok := entry.Fields.Get(key); !ok || got != want {
+					t.Errorf("fields[%q] = %v, want %v", key, got, want)
+				}
+			}
+			for _, key := range tt.absent {
+				if got, ok := entry.Fields.Get(key); ok {
+					t.Errorf("fields[%q] = %v, want it absent", key, got)
+				}
+			}
+		})
+	}
+}
+
+func TestSyslogBSDTimestampYear(t *testing.T) {
+	tests := []struct {
+		stamp string
+		now   time.Time
+		want  time.Time
+	}{
+		{"Oct 16 10:22:01", time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC), time.Date(2026, 10, 16, 10, 22, 1, 0, time.UTC)},
+		{"Dec 31 23:59:59", time.Date(2027, 1, 1, 0, 5, 0, 0, time.UTC), time.Date(2026, 12, 31, 23, 59, 59, 0, time.UTC)},
+		{"Jan  1 00:00:30", time.Date(2026, 12, 31, 23, 59, 0, 0, time.UTC), time.Date(2026, 1, 1, 0, 0, 30, 0, time.UTC)},
+		{"Oct 17 01:00:00", time.Date(2026, 10, 16, 23, 0, 0, 0, time.UTC), time.Date(2026, 10, 17, 1, 0, 0, 0, time.UTC)},
+		{"Oct 16 10:22:01.250", time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC), time.Date(2026, 10, 16, 10, 22, 1, 250e6, time.UTC)},
+	}
+
+	p := &SyslogLogParser{}
+	for _, tt := range tests {
+		got, err := p.parseBSDTimestamp(tt.stamp, tt.now)
+		if err != nil {
+			t.Errorf("parseBSDTimestamp(%q): %v", tt.stamp, err)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("parseBSDTimestamp(%q) at %s = %s, want %s", tt.stamp, tt.now, got, tt.want)
+		}
+	}
+}
+
+func TestSyslogCanParse(t *testing.T) {
+	tests := []struct {
+		line string
+		want bool
+	}{
+		{"<34>Oct 16 10:22:01 host sshd[812]: Failed password", true},
+		{"Oct 16 10:22:01 host sshd[812]: Failed password", true},
+		{"2026-10-16T10:22:01+00:00 host sshd[812]: Failed password", true},
+		{"2026-10-16T10:22:01Z ERROR something failed", false},
+		{`{"msg":"json"}`, false},
+		{"", false},
+	}
+
+	p := &SyslogLogParser{}
+	for _, tt := range tests {
+		if got := p.CanParse(tt.line); got != tt.want {
+			t.Errorf("CanParse(%q) = %v, want %v", tt.line, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,181 @@
+package reporter
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aadithyaa9/loganalyzer/internal/models"
+)
+
+// DefaultCSVColumns are written when no column set is selected
+var DefaultCSVColumns = []string{"timestamp", "level", "source", "message"}
+
+// csvColumns maps column names to their value extractors
+var csvColumns = map[string]func(*models.LogEntry) string{
+	"timestamp": func(e *models.LogEntry) string { return e.Timestamp.Format(time.RFC3339Nano) },
+	"level":     func(e *models.LogEntry) string { return e.Level.String() },
+	"source":    func(e *models.LogEntry) string { return e.Source },
+	"message":   func(e *models.LogEntry) string { return e.Message },
+	"raw":       func(e *models.LogEntry) string { return e.Raw },
+	"path":      func(e *models.LogEntry) string { return e.Path },
+	"line":      func(e *models.LogEntry) string { return strconv.Itoa(e.Line) },
+	"offset":    func(e *models.LogEntry) string { return strconv.FormatInt(e.Offset, 10) },
+	"location":  func(e *models.LogEntry) string { return e.Location() },
+	"kind":      entryKind,
+}
+
+// entryKind tells matches from the context around them
+func entryKind(e *models.LogEntry) string {
+	if e.Context {
+		return "context"
+	}
+	return "match"
+}
+
+// fieldColumnPrefix selects a structured field as a column ("fields.status");
+// "fields.*" expands to every field present in the entries
+const fieldColumnPrefix = "fields."
+
+// CSVReporter formats entries as RFC 4180 CSV
+type CSVReporter struct {
+	Columns       []string  // Columns to write, DefaultCSVColumns if empty
+	SummaryWriter io.Writer // Optional destination for level/source counts
+	SummaryOnly   bool      // Write only the summary CSV, to the report writer
+}
+
+// Name returns the reporter name
+func (r *CSVReporter) Name() string {
+	return "CSV"
+}
+
+// ParseCSVColumns parses a comma-separated column list and validates each name
+func ParseCSVColumns(spec string) ([]string, error) {
+	if strings.TrimSpace(spec) == "" {
+		return DefaultCSVColumns, nil
+	}
+
+	var columns []string
+	for _, col := range strings.Split(spec, ",") {
+		col = strings.TrimSpace(col)
+		if !strings.HasPrefix(col, fieldColumnPrefix) {
+			col = strings.ToLower(col)
+		}
+		if col == "" {
+			continue
+		}
+		if _, ok := csvColumns[col]; !ok && !strings.HasPrefix(col, fieldColumnPrefix) {
+			return nil, fmt.Errorf("unknown CSV column %q (valid: timestamp, level, source, message, raw, path, line, offset, location, kind, fields.<name>, fields.*)", col)
+		}
+		columns = append(columns, col)
+	}
+
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("no CSV columns selected")
+	}
+	return columns, nil
+}
+
+// Report writes one CSV row per entry, plus the summary CSV if configured
+func (r *CSVReporter) Report(entries []*models.LogEntry, stats *models.Statistics, writer io.Writer) error {
+	if r.SummaryOnly {
+		return writeCSVSummary(stats, writer)
+	}
+
+	columns := r.Columns
+	if len(columns) == 0 {
+		columns = DefaultCSVColumns
+	}
+	columns = expandFieldColumns(columns, entries)
+
+	w := csv.NewWriter(writer)
+	if err := w.Write(columns); err != nil {
+		return fmt.Errorf("failed to write CSV header: %w", err)
+	}
+
+	row := make([]string, len(columns))
+	for _, entry := range entries {
+		for i, col := range columns {
+			if key, ok := strings.CutPrefix(col, fieldColumnPrefix); ok {
+				row[i], _ = entry.Fields.GetString(key)
+				continue
+			}
+			extract, ok := csvColumns[col]
+			if !ok {
+				return fmt.Errorf("unknown CSV column %q", col)
+			}
+			row[i] = extract(entry)
+		}
+		if err := w.Write(row); err != nil {
+			return fmt.Errorf("failed to write CSV row: %w", err)
+		}
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return fmt.Errorf("failed to flush CSV: %w", err)
+	}
+
+	if r.SummaryWriter != nil {
+		return writeCSVSummary(stats, r.SummaryWriter)
+	}
+	return nil
+}
+
+// expandFieldColumns replaces "fields.*" with one column per field name seen
+func expandFieldColumns(columns []string, entries []*models.LogEntry) []string {
+	expanded := make([]string, 0, len(columns))
+	for _, col := range columns {
+		if col != fieldColumnPrefix+"*" {
+			expanded = append(expanded, col)
+			continue
+		}
+
+		seen := make(map[string]bool)
+		for _, entry := range entries {
+			for key := range entry.Fields {
+				seen[key] = true
+			}
+		}
+		keys := make([]string, 0, len(seen))
+		for key := range seen {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			expanded = append(expanded, fieldColumnPrefix+key)
+		}
+	}
+	return expanded
+}
+
+// writeCSVSummary writes level and source counts as "category,name,count" rows
+func writeCSVSummary(stats *models.Statistics, writer io.Writer) error {
+	w := csv.NewWriter(writer)
+	rows := [][]string{{"category", "name", "count"}}
+
+	levels := []models.LogLevel{models.DEBUG, models.INFO, models.WARN, models.ERROR, models.FATAL, models.UNKNOWN}
+	for _, level := range levels {
+		if count, ok := stats.LevelCounts[level]; ok {
+			rows = append(rows, []string{"level", level.String(), strconv.Itoa(count)})
+		}
+	}
+
+	sources := make([]string, 0, len(stats.SourceCounts))
+	for source := range stats.SourceCounts {
+		sources = append(sources, source)
+	}
+	sort.Strings(sources)
+	for _, source := range sources {
+		rows = append(rows, []string{"source", source, strconv.Itoa(stats.SourceCounts[source])})
+	}
+
+	if err := w.WriteAll(rows); err != nil {
+		return fmt.Errorf("failed to write CSV summary: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,168 @@
+package reporter
+
+import (
+	"bytes"
+	"encoding/csv"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aadithyaa9/loganalyzer/internal/models"
+)
+
+func TestCSVReporter(t *testing.T) {
+	ts := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	entry := func(message string, context bool) *models.LogEntry {
+		return &models.LogEntry{
+			Timestamp: ts,
+			Level:     models.ERROR,
+			Message:   message,
+			Source:    "app.log",
+			Path:      "/var/log/app.log",
+			Position:  models.Position{Line: 7, Offset: 120},
+			Fields:    models.Fields{"status": 503.0, "user": "bob"},
+			Context:   context,
+		}
+	}
+
+	tests := []struct {
+		name    string
+		columns []string
+		entries []*models.LogEntry
+		want    [][]string
+	}{
+		{
+			name:    "default columns",
+			entries: []*models.LogEntry{entry("disk full", false)},
+			want: [][]string{
+				{"timestamp", "level", "source", "message"},
+				{"2024-01-15T10:00:00Z", "ERROR", "app.log", "disk full"},
+			},
+		},
+		{
+			name:    "columns in the order given",
+			columns: []string{"message", "line", "level", "location", "offset", "path"},
+			entries: []*models.LogEntry{entry("x", false)},
+			want: [][]string{
+				{"message", "line", "level", "location", "offset", "path"},
+				{"x", "7", "ERROR", "/var/log/app.log:7", "120", "/var/log/app.log"},
+			},
+		},
+		{
+			name:    "messages that need quoting",
+			columns: []string{"message"},
+			entries: []*models.LogEntry{
+				entry("a, b", false),
+				entry(`said "hi"`, false),
+				entry("boom\n\tat Main.main(Main.java:1)", false),
+			},
+			want: [][]string{
+				{"message"},
+				{"a, b"},
+				{`said "hi"`},
+				{"boom\n\tat Main.main(Main.java:1)"},
+			},
+		},
+		{
+			name:    "kind of matches and context",
+			columns: []string{"kind", "message"},
+			entries: []*models.LogEntry{entry("before", true), entry("match", false), entry("after", true)},
+			want: [][]string{
+				{"kind", "message"},
+				{"context", "before"},
+				{"match", "match"},
+				{"context", "after"},
+			},
+		},
+		{
+			name:    "field columns",
+			columns: []string{"fields.status", "fields.*", "fields.missing"},
+			entries: []*models.LogEntry{entry("x", false)},
+			want: [][]string{
+				{"fields.status", "fields.status", "fields.user", "fields.missing"},
+				{"503", "503", "bob", ""},
+			},
+		},
+		{
+			name:    "no entries",
+			columns: []string{"level", "message"},
+			want:    [][]string{{"level", "message"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			r := &CSVReporter{Columns: tt.columns}
+			if err := r.Report(tt.entries, models.NewStatistics(), &out); err != nil {
+				t.Fatal(err)
+			}
+			got, err := csv.NewReader(&out).ReadAll()
+			if err != nil {
+				t.Fatalf("output is not valid CSV: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("rows = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCSVReporterQuoting(t *testing.T) {
+	var out bytes.Buffer
+	r := &CSVReporter{Columns: []string{"message"}}
+	entries := []*models.LogEntry{{Message: `a, "b"` + "\nc"}}
+	if err := r.Report(entries, models.NewStatistics(), &out); err != nil {
+		t.Fatal(err)
+	}
+	if want := "message\n\"a, \"\"b\"\"\nc\"\n"; out.String() != want {
+		t.Errorf("output = %q, want %q", out.String(), want)
+	}
+}
+
+func TestCSVSummary(t *testing.T) {
+	stats := models.NewStatistics()
+	for _, e := range []*models.LogEntry{
+		{Level: models.ERROR, Source: "b.log"},
+		{Level: models.INFO, Source: "a.log"},
+		{Level: models.ERROR, Source: "a.log"},
+	} {
+		stats.AddEntry(e)
+	}
+
+	var out bytes.Buffer
+	if err := (&CSVReporter{SummaryOnly: true}).Report(nil, stats, &out); err != nil {
+		t.Fatal(err)
+	}
+	want := "category,name,count\nlevel,INFO,1\nlevel,ERROR,2\nsource,a.log,2\nsource,b.log,1\n"
+	if out.String() != want {
+		t.Errorf("summary = %q, want %q", out.String(), want)
+	}
+}
+
+func TestParseCSVColumns(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    []string
+		wantErr string
+	}{
+		{"", DefaultCSVColumns, ""},
+		{" Level , MESSAGE,fields.Status ", []string{"level", "message", "fields.Status"}, ""},
+		{"level,,kind", []string{"level", "kind"}, ""},
+		{"level,bogus", nil, `unknown CSV column "bogus"`},
+		{" , ", nil, "no CSV columns selected"},
+	}
+	for _, tt := range tests {
+		got, err := ParseCSVColumns(tt.spec)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ParseCSVColumns(%q) error = %v, want %q", tt.spec, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseCSVColumns(%q) = %q, %v, want %q", tt.spec, got, err, tt.want)
+		}
+	}
+}
@@ -8,8 +8,14 @@ import (
 	"github.com/aadithyaa9/loganalyzer/internal/models"
 )
 
+// topPatternsLimit caps the number of error templates in the JSON report
+const topPatternsLimit = 10
+
 // JSONReporter formats output as JSON
-type JSONReporter struct{}
+type JSONReporter struct {
+	SummaryOnly  bool // Omit the entries array
+	ShowLocation bool // Add the path, line and byte offset of each entry
+}
 
 // Name returns the reporter name
 func (r *JSONReporter) Name() string {
@@ -22,7 +28,14 @@ func (r *JSONReporter) Report(entries []*models.LogEntry, stats *models.Statisti
 
 	encoder := json.NewEncoder(writer)
 	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false) // Keep template placeholders like <IP> readable
 
+	if r.SummaryOnly {
+		return encoder.Encode(JSONSummaryReport{
+			Summary:    report.Summary,
+			Statistics: report.Statistics,
+		})
+	}
 	return encoder.Encode(report)
 }
 
@@ -33,6 +46,12 @@ type JSONReport struct {
 	Entries    []EntryJSON `json:"entries"`
 }
 
+// JSONSummaryReport is the JSON structure without entries
+type JSONSummaryReport struct {
+	Summary    Summary   `json:"summary"`
+	Statistics StatsJSON `json:"statistics"`
+}
+
 // Summary holds summary information
 type Summary struct {
 	TotalEntries   int     `json:"total_entries"`
@@ -40,13 +59,42 @@ type Summary struct {
 	BytesProcessed int64   `json:"bytes_processed"`
 	ProcessingTime string  `json:"processing_time"`
 	EntriesPerSec  float64 `json:"entries_per_second"`
+	ThroughputMBs  float64 `json:"throughput_mb_per_second"`
 }
 
 // StatsJSON holds statistics in JSON format
 type StatsJSON struct {
-	LevelCounts  map[string]int `json:"level_counts"`
-	SourceCounts map[string]int `json:"source_counts"`
-	TimeRange    TimeRange      `json:"time_range"`
+	LevelCounts   map[string]int    `json:"level_counts"`
+	SourceCounts  map[string]int    `json:"source_counts"`
+	SourceParsers map[string]string `json:"source_parsers,omitempty"`
+	ParseQuality  ParseQualityJSON  `json:"parse_quality"`
+	TimeRange     TimeRange         `json:"time_range"`
+	TopPatterns   []PatternJSON     `json:"top_patterns,omitempty"`
+}
+
+// ParseQualityJSON reports parse failures and problems, overall and for
+// each source that had any
+type ParseQualityJSON struct {
+	QualityCountsJSON
+	Sources map[string]QualityCountsJSON `json:"sources,omitempty"`
+}
+
+// QualityCountsJSON holds the parse quality counts of one scope
+type QualityCountsJSON struct {
+	Records          int     `json:"records"`
+	Failed           int     `json:"failed"`
+	Mismatched       int     `json:"mismatched"`
+	TimestampMissing int     `json:"timestamp_missing"`
+	TimestampInvalid int     `json:"timestamp_invalid"`
+	UnknownLevel     int     `json:"unknown_level"`
+	LevelDefault     int     `json:"level_default"`
+	BadRatio         float64 `json:"bad_ratio"`
+}
+
+// PatternJSON represents a mined error template and its count
+type PatternJSON struct {
+	Pattern string `json:"pattern"`
+	Count   int    `json:"count"`
 }
 
 // TimeRange holds time range information
@@ -58,10 +106,20 @@ type TimeRange struct {
 
 // EntryJSON represents a log entry in JSON format
 type EntryJSON struct {
-	Timestamp string `json:"timestamp"`
-	Level     string `json:"level"`
-	Message   string `json:"message"`
-	Source    string `json:"source"`
+	Timestamp string        `json:"timestamp"`
+	Level     string        `json:"level"`
+	Message   string        `json:"message"`
+	Source    string        `json:"source"`
+	Fields    models.Fields `json:"fields,omitempty"`
+	Location  *LocationJSON `json:"location,omitempty"`
+	Context   bool          `json:"context,omitempty"` // Shown around a match, not a match itself
+}
+
+// LocationJSON is where an entry starts in its file
+type LocationJSON struct {
+	Path   string `json:"path"`
+	Line   int    `json:"line"`
+	Offset int64  `json:"offset"`
 }
 
 // buildReport builds the JSON report structure
@@ -74,9 +132,8 @@ func (r *JSONReporter) buildReport(entries []*models.LogEntry, stats *models.Sta
 		ProcessingTime: stats.ProcessingTime.String(),
 	}
 
-	if stats.ProcessingTime.Seconds() > 0 {
-		summary.EntriesPerSec = float64(stats.TotalEntries) / stats.ProcessingTime.Seconds()
-	}
+	summary.EntriesPerSec = stats.EntriesPerSecond()
+	summary.ThroughputMBs = stats.Throughput()
 
 	// Build statistics
 	levelCounts := make(map[string]int)
@@ -85,8 +142,9 @@ func (r *JSONReporter) buildReport(entries []*models.LogEntry, stats *models.Sta
 	}
 
 	statistics := StatsJSON{
-		LevelCounts:  levelCounts,
-		SourceCounts: stats.SourceCounts,
+		LevelCounts:   levelCounts,
+		SourceCounts:  stats.SourceCounts,
+		SourceParsers: stats.SourceParsers(),
 		TimeRange: TimeRange{
 			Start:    formatTime(stats.FirstTimestamp),
 			End:      formatTime(stats.LastTimestamp),
@@ -94,6 +152,24 @@ func (r *JSONReporter) buildReport(entries []*models.LogEntry, stats *models.Sta
 		},
 	}
 
+	statistics.ParseQuality.QualityCountsJSON = qualityCounts(stats.TotalParseQuality())
+	for source, quality := range stats.ParseQualityBySource() {
+		if quality.Clean() {
+			continue
+		}
+		if statistics.ParseQuality.Sources == nil {
+			statistics.ParseQuality.Sources = make(map[string]QualityCountsJSON)
+		}
+		statistics.ParseQuality.Sources[source] = qualityCounts(quality)
+	}
+
+	for _, p := range stats.TopPatterns(topPatternsLimit) {
+		statistics.TopPatterns = append(statistics.TopPatterns, PatternJSON{
+			Pattern: p.Pattern,
+			Count:   p.Count,
+		})
+	}
+
 	// Build entries
 	jsonEntries := make([]EntryJSON, len(entries))
 	for i, entry := range entries {
@@ -102,6 +178,15 @@ func (r *JSONReporter) buildReport(entries []*models.LogEntry, stats *models.Sta
 			Level:     entry.Level.String(),
 			Message:   entry.Message,
 			Source:    entry.Source,
+			Fields:    entry.Fields,
+			Context:   entry.Context,
+		}
+		if r.ShowLocation {
+			jsonEntries[i].Location = &LocationJSON{
+				Path:   entry.Path,
+				Line:   entry.Line,
+				Offset: entry.Offset,
+			}
 		}
 	}
 
@@ -112,6 +197,20 @@ func (r *JSONReporter) buildReport(entries []*models.LogEntry, stats *models.Sta
 	}
 }
 
+// qualityCounts converts parse quality counts for the JSON report
+func qualityCounts(q models.ParseQuality) QualityCountsJSON {
+	return QualityCountsJSON{
+		Records:          q.Records,
+		Failed:           q.Failed,
+		Mismatched:       q.Mismatched,
+		TimestampMissing: q.TimestampMissing,
+		TimestampInvalid: q.TimestampInvalid,
+		UnknownLevel:     q.UnknownLevel,
+		LevelDefault:     q.LevelDefault,
+		BadRatio:         q.BadRatio(),
+	}
+}
+
 // formatTime formats a time or returns empty string if zero
 func formatTime(t time.Time) string {
 	if t.IsZero() {
@@ -39,6 +39,8 @@ func GetReporter(format OutputFormat) Reporter {
 	switch format {
 	case JSONFormat:
 		return &JSONReporter{}
+	case CSVFormat:
+		return &CSVReporter{}
 	case TableFormat:
 		return &TableReporter{}
 	default:
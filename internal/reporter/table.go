@@ -3,14 +3,28 @@ package reporter
 import (
 	"fmt"
 	"io"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/aadithyaa9/loganalyzer/internal/models"
 	"github.com/fatih/color"
 )
 
+// maxTableEntries is the number of entries the table shows
+const maxTableEntries = 50
+
 // TableReporter formats output as a readable table
-type TableReporter struct{}
+type TableReporter struct {
+	SummaryOnly  bool // Print statistics without entries
+	ShowLocation bool // Print the path:line of each entry
+}
+
+// MaxEntries returns how many entries the table can display, so callers
+// only need to retain that many
+func (r *TableReporter) MaxEntries() int {
+	return maxTableEntries
+}
 
 // Name returns the reporter name
 func (r *TableReporter) Name() string {
@@ -22,6 +36,10 @@ func (r *TableReporter) Report(entries []*models.LogEntry, stats *models.Statist
 	// Print statistics first
 	fmt.Fprintln(writer, stats.Summary())
 
+	if r.SummaryOnly {
+		return nil
+	}
+
 	// Print entries
 	if len(entries) == 0 {
 		fmt.Fprintln(writer, "\n✨ No entries found matching the criteria")
@@ -31,21 +49,46 @@ func (r *TableReporter) Report(entries []*models.LogEntry, stats *models.Statist
 	fmt.Fprintln(writer, "\n📋 Log Entries")
 	fmt.Fprintln(writer, strings.Repeat("─", 80))
 
-	// Determine how many entries to show
-	maxEntries := 50
-	if len(entries) > maxEntries {
-		fmt.Fprintf(writer, "Showing first %d of %d entries\n\n", maxEntries, len(entries))
-		entries = entries[:maxEntries]
+	// Determine how many entries to show; entries may already be a
+	// bounded preview, so the total comes from the statistics, which
+	// count matches only
+	total := countMatches(entries)
+	if stats.TotalEntries > total {
+		total = stats.TotalEntries
+	}
+	if len(entries) > maxTableEntries {
+		entries = entries[:maxTableEntries]
+	}
+	if shown := countMatches(entries); total > shown {
+		if context := len(entries) - shown; context > 0 {
+			fmt.Fprintf(writer, "Showing first %d of %d matching entries, with %d context entries\n\n", shown, total, context)
+		} else {
+			fmt.Fprintf(writer, "Showing first %d of %d entries\n\n", shown, total)
+		}
 	}
 
-	// Print each entry
+	// Print each entry, separating runs of matches and their context
 	for i, entry := range entries {
+		if entry.GroupStart && i > 0 {
+			fmt.Fprintln(writer, color.New(color.FgHiBlack).Sprint("--"))
+		}
 		r.printEntry(writer, i+1, entry)
 	}
 
 	return nil
 }
 
+// countMatches counts the entries that are not context
+func countMatches(entries []*models.LogEntry) int {
+	count := 0
+	for _, entry := range entries {
+		if !entry.Context {
+			count++
+		}
+	}
+	return count
+}
+
 // printEntry prints a single log entry with color
 func (r *TableReporter) printEntry(writer io.Writer, index int, entry *models.LogEntry) {
 	timestamp := entry.Timestamp.Format("2006-01-02 15:04:05")
@@ -67,19 +110,36 @@ func (r *TableReporter) printEntry(writer io.Writer, index int, entry *models.Lo
 		levelStr = fmt.Sprintf("%-5s", entry.Level.String())
 	}
 
-	// Truncate message if too long
+	// Show only the first line of multiline entries
 	message := entry.Message
+	if i := strings.IndexByte(message, '\n'); i >= 0 {
+		extra := strings.Count(message[i:], "\n")
+		message = fmt.Sprintf("%s (+%d lines)", message[:i], extra)
+	}
+
+	// Truncate message if too long
 	if len(message) > 70 {
 		message = message[:67] + "..."
 	}
 
-	fmt.Fprintf(writer, "%3d. [%s] %s | %s | %s\n",
+	// Context entries are marked like grep's: "-" instead of "." and dimmed
+	marker := "."
+	if entry.Context {
+		marker = "-"
+		message = color.New(color.FgHiBlack).Sprint(message)
+	}
+
+	fmt.Fprintf(writer, "%3d%s [%s] %s | %s | %s\n",
 		index,
+		marker,
 		timestamp,
 		levelStr,
 		color.New(color.FgHiBlack).Sprintf("%-15s", truncate(entry.Source, 15)),
 		message,
 	)
+	if r.ShowLocation {
+		fmt.Fprintf(writer, "     %s\n", color.New(color.FgHiBlack).Sprintf("↳ %s", entry.Location()))
+	}
 }
 
 // truncate truncates a string to a maximum length
@@ -90,41 +150,78 @@ func truncate(s string, maxLen int) string {
 	return s[:maxLen-3] + "..."
 }
 
-// PrintTopErrors prints the most common error patterns
+// PrintTopErrors prints the most common error templates
 func PrintTopErrors(stats *models.Statistics, writer io.Writer, limit int) {
 	fmt.Fprintln(writer, "\n🔥 Top Error Patterns")
 	fmt.Fprintln(writer, strings.Repeat("─", 80))
 
-	// Get pattern counts
-	type patternCount struct {
-		pattern string
-		count   int
+	patterns := stats.TopPatterns(limit)
+	if len(patterns) == 0 {
+		fmt.Fprintln(writer, "No error patterns found")
+		return
 	}
 
-	patterns := make([]patternCount, 0)
-	for pattern, count := range stats.PatternCounts {
-		patterns = append(patterns, patternCount{pattern, count})
+	for i, p := range patterns {
+		fmt.Fprintf(writer, "%2d. %s  %s\n",
+			i+1,
+			p.Pattern,
+			color.New(color.FgYellow).Sprintf("x %s", formatCount(p.Count)),
+		)
 	}
+}
 
-	// Simple bubble sort for small data
-	for i := 0; i < len(patterns); i++ {
-		for j := i + 1; j < len(patterns); j++ {
-			if patterns[j].count > patterns[i].count {
-				patterns[i], patterns[j] = patterns[j], patterns[i]
-			}
+// formatCount formats a count with thousands separators (4312 -> "4,312")
+func formatCount(n int) string {
+	s := strconv.Itoa(n)
+	if n < 0 {
+		return "-" + formatCount(-n)
+	}
+
+	var b strings.Builder
+	for i, c := range s {
+		if i > 0 && (len(s)-i)%3 == 0 {
+			b.WriteByte(',')
 		}
+		b.WriteRune(c)
 	}
+	return b.String()
+}
+
+// PrintParseQuality prints parse failures and problems, listing the
+// sources that had any
+func PrintParseQuality(stats *models.Statistics, writer io.Writer) {
+	fmt.Fprintln(writer, "\n🧪 Parse Quality")
+	fmt.Fprintln(writer, strings.Repeat("─", 80))
 
-	// Print top N
-	if limit > len(patterns) {
-		limit = len(patterns)
+	total := stats.TotalParseQuality()
+	fmt.Fprintf(writer, "Records:            %s\n", formatCount(total.Records))
+	fmt.Fprintf(writer, "Unparseable:        %s\n", formatCount(total.Failed))
+	fmt.Fprintf(writer, "Parser mismatch:    %s\n", formatCount(total.Mismatched))
+	fmt.Fprintf(writer, "Missing timestamp:  %s\n", formatCount(total.TimestampMissing))
+	fmt.Fprintf(writer, "Invalid timestamp:  %s\n", formatCount(total.TimestampInvalid))
+	fmt.Fprintf(writer, "Unknown level:      %s\n", formatCount(total.UnknownLevel))
+	fmt.Fprintf(writer, "Default level:      %s\n", formatCount(total.LevelDefault))
+	if total.Clean() {
+		fmt.Fprintln(writer, color.New(color.FgGreen).Sprint("✅ Every record parsed cleanly"))
+		return
 	}
 
-	for i := 0; i < limit; i++ {
-		fmt.Fprintf(writer, "%2d. %s: %s\n",
-			i+1,
-			color.New(color.FgYellow).Sprintf("%5d occurrences", patterns[i].count),
-			patterns[i].pattern,
+	qualities := stats.ParseQualityBySource()
+	sources := make([]string, 0, len(qualities))
+	for source, quality := range qualities {
+		if !quality.Clean() {
+			sources = append(sources, source)
+		}
+	}
+	sort.Strings(sources)
+
+	fmt.Fprintln(writer)
+	for _, source := range sources {
+		q := qualities[source]
+		fmt.Fprintf(writer, "%-30s: %s bad (%d failed, %d mismatched, %d no time, %d bad time, %d no level)\n",
+			source,
+			color.New(color.FgYellow).Sprintf("%5.1f%%", q.BadRatio()*100),
+			q.Failed, q.Mismatched, q.TimestampMissing, q.TimestampInvalid, q.UnknownLevel,
 		)
 	}
 }
@@ -134,7 +231,12 @@ func PrintSourceBreakdown(stats *models.Statistics, writer io.Writer) {
 	fmt.Fprintln(writer, "\n📁 Breakdown by Source")
 	fmt.Fprintln(writer, strings.Repeat("─", 80))
 
+	parsers := stats.SourceParsers()
 	for source, count := range stats.SourceCounts {
+		if p, ok := parsers[source]; ok {
+			fmt.Fprintf(writer, "%-30s: %6d entries  (%s)\n", source, count, p)
+			continue
+		}
 		fmt.Fprintf(writer, "%-30s: %6d entries\n", source, count)
 	}
 }
@@ -0,0 +1,72 @@
+package reporter
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/aadithyaa9/loganalyzer/internal/models"
+)
+
+func TestTableReporterShowingHeader(t *testing.T) {
+	// entries builds n runs of one match between two context entries
+	entries := func(n int) []*models.LogEntry {
+		var result []*models.LogEntry
+		for i := 0; i < n; i++ {
+			result = append(result,
+				&models.LogEntry{Level: models.INFO, Message: "before", Context: true, GroupStart: true},
+				&models.LogEntry{Level: models.ERROR, Message: "match"},
+				&models.LogEntry{Level: models.INFO, Message: "after", Context: true},
+			)
+		}
+		return result
+	}
+
+	tests := []struct {
+		name    string
+		entries []*models.LogEntry
+		matches int
+		header  string
+	}{
+		{"all shown", entries(3), 3, ""},
+		{"context cut off", entries(30), 30, "Showing first 17 of 30 matching entries, with 33 context entries"},
+		{"bounded preview", entries(5), 200, "Showing first 5 of 200 matching entries, with 10 context entries"},
+		{"no entries", nil, 0, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stats := models.NewStatistics()
+			stats.TotalEntries = tt.matches
+
+			var out bytes.Buffer
+			if err := (&TableReporter{}).Report(tt.entries, stats, &out); err != nil {
+				t.Fatal(err)
+			}
+			got := strings.Contains(out.String(), "Showing first")
+			if tt.header == "" && got {
+				t.Errorf("unexpected header in:\n%s", out.String())
+			}
+			if tt.header != "" && !strings.Contains(out.String(), tt.header) {
+				t.Errorf("output lacks %q:\n%s", tt.header, out.String())
+			}
+		})
+	}
+}
+
+func TestTableReporterShowingHeaderWithoutContext(t *testing.T) {
+	var entries []*models.LogEntry
+	for i := 0; i < 60; i++ {
+		entries = append(entries, &models.LogEntry{Level: models.ERROR, Message: "match"})
+	}
+	stats := models.NewStatistics()
+	stats.TotalEntries = len(entries)
+
+	var out bytes.Buffer
+	if err := (&TableReporter{}).Report(entries, stats, &out); err != nil {
+		t.Fatal(err)
+	}
+	if want := "Showing first 50 of 60 entries\n"; !strings.Contains(out.String(), want) {
+		t.Errorf("output lacks %q", want)
+	}
+}
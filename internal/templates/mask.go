@@ -0,0 +1,56 @@
+package templates
+
+import (
+	"regexp"
+	"strings"
+)
+
+// masker replaces one kind of variable token with a placeholder
+type masker struct {
+	hint        string // The regex is skipped unless the message contains one of these
+	re          *regexp.Regexp
+	replacement string
+}
+
+// maskers run in order; more specific shapes go before plain numbers
+var maskers = []masker{
+	{`"`, regexp.MustCompile(`"[^"]*"`), "<STR>"},
+	{`'`, regexp.MustCompile(`(^|[\s=:(\[])'[^']*'`), "${1}<STR>"},
+	{"-", regexp.MustCompile(`[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`), "<UUID>"},
+	{".", regexp.MustCompile(`\b\d{1,3}(\.\d{1,3}){3}\b`), "<IP>"},
+	{"xX", regexp.MustCompile(`\b0[xX][0-9a-fA-F]+\b`), "<HEX>"},
+}
+
+// hexWord matches bare hex runs such as commit hashes and request IDs
+var hexWord = regexp.MustCompile(`\b[0-9a-fA-F]{8,}\b`)
+
+// number matches integers and decimals not embedded in identifiers
+var number = regexp.MustCompile(`(^|[^A-Za-z0-9_<])-?\d+(\.\d+)?`)
+
+// Mask replaces numbers, UUIDs, IPs, hex values and quoted strings with placeholders
+func Mask(message string) string {
+	if !hasDigit(message) && !strings.ContainsAny(message, `"'`) {
+		return message
+	}
+
+	for _, m := range maskers {
+		if strings.ContainsAny(message, m.hint) {
+			message = m.re.ReplaceAllString(message, m.replacement)
+		}
+	}
+
+	// A bare hex run must mix digits and letters, otherwise it is a number or a word
+	message = hexWord.ReplaceAllStringFunc(message, func(s string) string {
+		if hasDigit(s) && strings.IndexFunc(s, isHexLetter) >= 0 {
+			return "<HEX>"
+		}
+		return s
+	})
+
+	return number.ReplaceAllString(message, "${1}<NUM>")
+}
+
+// isHexLetter reports whether r is one of a-f or A-F
+func isHexLetter(r rune) bool {
+	return (r >= 'a' && r <= 'f') || (r >= 'A' && r <= 'F')
+}
@@ -0,0 +1,229 @@
+package templates
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Wildcard replaces tokens that vary between messages of the same template
+const Wildcard = "<*>"
+
+// DefaultMaxClusters bounds the clusters a Miner keeps, so messages that
+// never repeat cannot grow it without limit
+const DefaultMaxClusters = 1000
+
+// Cluster is a group of messages sharing one template
+type Cluster struct {
+	ID     int
+	Tokens []string
+	Count  int
+
+	leaf *node // Tree node holding the cluster
+}
+
+// Template returns the cluster's template as a single string
+func (c *Cluster) Template() string {
+	return strings.Join(c.Tokens, " ")
+}
+
+// Miner clusters log messages into templates online (Drain algorithm).
+// Messages are masked, tokenized and routed through a fixed-depth prefix
+// tree keyed by token count and leading tokens; within a leaf the most
+// similar cluster absorbs the message, turning differing tokens into <*>.
+// Once maxClusters are kept, a new cluster replaces the least counted one.
+type Miner struct {
+	mu          sync.Mutex
+	depth       int     // Number of leading tokens used for routing
+	similarity  float64 // Minimum fraction of equal tokens to join a cluster
+	maxChildren int     // Children per tree node before falling back to <*>
+	maxClusters int     // Clusters kept before the least counted is evicted
+	root        map[int]*node
+	clusters    []*Cluster
+	created     int // Clusters created, evicted ones included
+}
+
+// node is an internal prefix tree node
+type node struct {
+	children map[string]*node
+	clusters []*Cluster
+}
+
+// NewMiner creates a Miner with the usual Drain defaults
+func NewMiner() *Miner {
+	return &Miner{
+		depth:       4,
+		similarity:  0.4,
+		maxChildren: 100,
+		maxClusters: DefaultMaxClusters,
+		root:        make(map[int]*node),
+	}
+}
+
+// Add mines a message and returns the cluster it was assigned to (thread-safe)
+func (m *Miner) Add(message string) *Cluster {
+	tokens := strings.Fields(Mask(message))
+	if len(tokens) == 0 {
+		return nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	leaf := m.route(tokens)
+
+	// Find the most similar cluster in the leaf
+	var best *Cluster
+	bestSim, bestParams := -1.0, -1
+	for _, c := range leaf.clusters {
+		sim, params := similarity(c.Tokens, tokens)
+		if sim > bestSim || (sim == bestSim && params > bestParams) {
+			best, bestSim, bestParams = c, sim, params
+		}
+	}
+
+	if best != nil && bestSim >= m.similarity {
+		for i, tok := range tokens {
+			if best.Tokens[i] != tok {
+				best.Tokens[i] = Wildcard
+			}
+		}
+		best.Count++
+		return best
+	}
+
+	if len(m.clusters) >= m.maxClusters {
+		m.evict()
+	}
+	m.created++
+	c := &Cluster{
+		ID:     m.created,
+		Tokens: append([]string(nil), tokens...),
+		Count:  1,
+		leaf:   leaf,
+	}
+	leaf.clusters = append(leaf.clusters, c)
+	m.clusters = append(m.clusters, c)
+	return c
+}
+
+// Clusters returns a snapshot of all clusters sorted by count (thread-safe)
+func (m *Miner) Clusters() []Cluster {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	result := make([]Cluster, len(m.clusters))
+	for i, c := range m.clusters {
+		result[i] = Cluster{
+			ID:     c.ID,
+			Tokens: append([]string(nil), c.Tokens...),
+			Count:  c.Count,
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Count > result[j].Count
+	})
+	return result
+}
+
+// Counts returns template counts keyed by template string (thread-safe)
+func (m *Miner) Counts() map[string]int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	counts := make(map[string]int, len(m.clusters))
+	for _, c := range m.clusters {
+		counts[c.Template()] += c.Count
+	}
+	return counts
+}
+
+// Len returns the number of clusters (thread-safe)
+func (m *Miner) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.clusters)
+}
+
+// Created returns how many clusters were ever created, evicted ones
+// included; cluster IDs run from 1 to it (thread-safe)
+func (m *Miner) Created() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.created
+}
+
+// evict removes the least counted cluster, the oldest of equals
+func (m *Miner) evict() {
+	victim := 0
+	for i, c := range m.clusters {
+		if c.Count < m.clusters[victim].Count {
+			victim = i
+		}
+	}
+	c := m.clusters[victim]
+	m.clusters = append(m.clusters[:victim], m.clusters[victim+1:]...)
+	for i, other := range c.leaf.clusters {
+		if other == c {
+			c.leaf.clusters = append(c.leaf.clusters[:i], c.leaf.clusters[i+1:]...)
+			break
+		}
+	}
+}
+
+// route walks the prefix tree to the leaf for the given tokens, creating nodes as needed
+func (m *Miner) route(tokens []string) *node {
+	n, ok := m.root[len(tokens)]
+	if !ok {
+		n = &node{children: make(map[string]*node)}
+		m.root[len(tokens)] = n
+	}
+
+	for i := 0; i < m.depth && i < len(tokens); i++ {
+		key := tokens[i]
+		if hasDigit(key) {
+			key = Wildcard
+		}
+
+		child, ok := n.children[key]
+		if !ok {
+			if len(n.children) >= m.maxChildren {
+				key = Wildcard
+				child = n.children[key]
+			}
+			if child == nil {
+				child = &node{children: make(map[string]*node)}
+				n.children[key] = child
+			}
+		}
+		n = child
+	}
+
+	return n
+}
+
+// similarity returns the fraction of equal tokens and the number of wildcards
+func similarity(template, tokens []string) (float64, int) {
+	equal, params := 0, 0
+	for i, tok := range template {
+		if tok == Wildcard {
+			params++
+			continue
+		}
+		if tok == tokens[i] {
+			equal++
+		}
+	}
+	return float64(equal) / float64(len(template)), params
+}
+
+// hasDigit reports whether a token contains a digit
+func hasDigit(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= '0' && s[i] <= '9' {
+			return true
+		}
+	}
+	return false
+}
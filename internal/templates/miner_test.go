@@ -0,0 +1,125 @@
+package templates
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMask(t *testing.T) {
+	tests := []struct {
+		message string
+		want    string
+	}{
+		{"connection to 10.0.0.12:5432 refused", "connection to <IP>:<NUM> refused"},
+		{"request 3f2a9c1e-1b2c-4d5e-8f90-a1b2c3d4e5f6 failed", "request <UUID> failed"},
+		{"bad address 0xDEADBEEF", "bad address <HEX>"},
+		{"commit 9fceb02d0ae598e9 not found", "commit <HEX> not found"},
+		{`user "alice" not found`, "user <STR> not found"},
+		{"key='value' rejected", "key=<STR> rejected"},
+		{"took 12.5ms after -3 retries", "took <NUM>ms after <NUM> retries"},
+		{"worker2 stopped", "worker2 stopped"},
+		{"no variables here", "no variables here"},
+	}
+
+	for _, tt := range tests {
+		if got := Mask(tt.message); got != tt.want {
+			t.Errorf("Mask(%q) = %q, want %q", tt.message, got, tt.want)
+		}
+	}
+}
+
+func TestMinerClusters(t *testing.T) {
+	m := NewMiner()
+	messages := []string{
+		"connection to 10.0.0.1:5432 refused",
+		"connection to 10.0.0.2:5432 refused",
+		"connection to 192.168.1.7:6379 refused",
+		"failed login for user alice",
+		"failed login for user bob",
+		"disk full on /dev/sda1",
+		"connection to 10.0.0.3:5432 refused",
+	}
+	for _, message := range messages {
+		m.Add(message)
+	}
+
+	want := map[string]int{
+		"connection to <IP>:<NUM> refused": 4,
+		"failed login for user <*>":        2,
+		"disk full on /dev/sda1":           1,
+	}
+	got := m.Counts()
+	if len(got) != len(want) {
+		t.Fatalf("Counts() = %v, want %v", got, want)
+	}
+	for template, count := range want {
+		if got[template] != count {
+			t.Errorf("Counts()[%q] = %d, want %d (all: %v)", template, got[template], count, got)
+		}
+	}
+
+	clusters := m.Clusters()
+	if clusters[0].Template() != "connection to <IP>:<NUM> refused" || clusters[0].Count != 4 {
+		t.Errorf("Clusters()[0] = %q x %d, want the connection template first", clusters[0].Template(), clusters[0].Count)
+	}
+}
+
+func TestMinerKeepsDifferentShapesApart(t *testing.T) {
+	m := NewMiner()
+	m.Add("cache miss for key users")
+	m.Add("timeout talking to the cache")
+	m.Add("cache miss for key orders")
+
+	if m.Len() != 2 {
+		t.Errorf("Len() = %d, want 2 (clusters: %v)", m.Len(), m.Counts())
+	}
+	if m.Add("") != nil {
+		t.Errorf("Add(\"\") returned a cluster, want nil")
+	}
+}
+
+func TestMinerCapsClusters(t *testing.T) {
+	m := NewMiner()
+	m.maxClusters = 3
+	for i := 0; i < 5; i++ {
+		m.Add("connection refused")
+	}
+	m.Add("disk full")
+	// Messages of distinct lengths never share a cluster
+	for n := 3; n < 50; n++ {
+		m.Add(strings.Repeat("unique ", n))
+		if m.Len() > 3 {
+			t.Fatalf("Len() = %d after %d messages, want at most 3", m.Len(), n)
+		}
+	}
+
+	clusters := m.Clusters()
+	if clusters[0].Template() != "connection refused" || clusters[0].Count != 5 {
+		t.Errorf("Clusters()[0] = %q x %d, want the frequent template kept", clusters[0].Template(), clusters[0].Count)
+	}
+	if m.Created() != 49 || clusters[len(clusters)-1].ID != 49 {
+		t.Errorf("Created() = %d, last ID %d, want 49", m.Created(), clusters[len(clusters)-1].ID)
+	}
+
+	// An evicted cluster is gone from the tree too: its message starts over
+	if c := m.Add("disk full"); c.Count != 1 || c.ID != 50 {
+		t.Errorf("re-adding an evicted message gave %q x %d (ID %d), want a new cluster", c.Template(), c.Count, c.ID)
+	}
+
+	m = NewMiner()
+	for n := 0; n < 3*DefaultMaxClusters; n++ {
+		m.Add(word(n))
+	}
+	if m.Len() != DefaultMaxClusters {
+		t.Errorf("Len() = %d, want the default cap of %d", m.Len(), DefaultMaxClusters)
+	}
+}
+
+// word returns a distinct word without digits for every n
+func word(n int) string {
+	b := []byte{byte('a' + n%26)}
+	for n /= 26; n > 0; n /= 26 {
+		b = append(b, byte('a'+n%26))
+	}
+	return string(b)
+}
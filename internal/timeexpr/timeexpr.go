@@ -0,0 +1,231 @@
+package timeexpr
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aadithyaa9/loganalyzer/internal/parser"
+)
+
+// durationPart matches one "<number><unit>" component of a relative duration
+var durationPart = regexp.MustCompile(`(\d+(?:\.\d+)?)(ms|s|m|h|d|w)`)
+
+// offsetZone matches fixed UTC offsets such as +05:30 or -0800
+var offsetZone = regexp.MustCompile(`^([+-])(\d{2}):?(\d{2})$`)
+
+// weekdays maps weekday names and abbreviations to time.Weekday
+var weekdays = map[string]time.Weekday{
+	"sunday": time.Sunday, "sun": time.Sunday,
+	"monday": time.Monday, "mon": time.Monday,
+	"tuesday": time.Tuesday, "tue": time.Tuesday,
+	"wednesday": time.Wednesday, "wed": time.Wednesday,
+	"thursday": time.Thursday, "thu": time.Thursday,
+	"friday": time.Friday, "fri": time.Friday,
+	"saturday": time.Saturday, "sat": time.Saturday,
+}
+
+// LoadLocation resolves a timezone name: "" or "local", "UTC",
+// a fixed offset like "+05:30", or an IANA name like "Europe/Berlin"
+func LoadLocation(name string) (*time.Location, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "local":
+		return time.Local, nil
+	case "utc", "z":
+		return time.UTC, nil
+	}
+
+	if m := offsetZone.FindStringSubmatch(name); m != nil {
+		hours, _ := strconv.Atoi(m[2])
+		minutes, _ := strconv.Atoi(m[3])
+		offset := hours*3600 + minutes*60
+		if m[1] == "-" {
+			offset = -offset
+		}
+		return time.FixedZone(name, offset), nil
+	}
+
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("unknown timezone %q: %w", name, err)
+	}
+	return loc, nil
+}
+
+// Parse resolves a time expression relative to now, in the given location.
+//
+// Supported forms:
+//
+//	2024-01-20T15:04:05Z, 2024-01-20 15:04:05   absolute (naive times use loc)
+//	2024-01-20                                  midnight of that day
+//	2024-01-20 09:00                            a clock time on that day (in loc)
+//	1h, 30m, 2d, 1w, 1h30m, "2h ago"            that long before now
+//	now, today, yesterday, tomorrow             midnight of the day (except now)
+//	monday, last monday                         midnight of the most recent past monday
+//	15:04, yesterday 09:00, last monday 09:00   a clock time on that day
+func Parse(expr string, now time.Time, loc *time.Location) (time.Time, error) {
+	if loc == nil {
+		loc = time.Local
+	}
+	now = now.In(loc)
+
+	s := strings.TrimSpace(expr)
+	if s == "" {
+		return time.Time{}, fmt.Errorf("empty time expression")
+	}
+
+	// Absolute timestamps in the formats the parsers understand
+	if t, err := parser.ParseTimestampInLocation(s, loc); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", s, loc); err == nil {
+		return t, nil
+	}
+
+	lower := strings.ToLower(s)
+	if lower == "now" {
+		return now, nil
+	}
+
+	// Relative durations: "1h", "90m", "2d", "1h30m", "3h ago"
+	if d, ok := parseDuration(strings.TrimSuffix(lower, " ago")); ok {
+		return now.Add(-d), nil
+	}
+
+	// Day expressions with an optional clock time
+	fields := strings.Fields(lower)
+	clock := ""
+	if len(fields) > 1 && strings.Contains(fields[len(fields)-1], ":") {
+		clock = fields[len(fields)-1]
+		fields = fields[:len(fields)-1]
+	}
+
+	var day time.Time
+	date, dateErr := time.ParseInLocation("2006-01-02", fields[0], loc)
+	switch {
+	case len(fields) == 1 && dateErr == nil:
+		day = date
+	case len(fields) == 1 && strings.Contains(fields[0], ":"):
+		// Bare clock time means today
+		clock = fields[0]
+		day = midnight(now)
+	case len(fields) == 1 && fields[0] == "today":
+		day = midnight(now)
+	case len(fields) == 1 && fields[0] == "yesterday":
+		day = midnight(now).AddDate(0, 0, -1)
+	case len(fields) == 1 && fields[0] == "tomorrow":
+		day = midnight(now).AddDate(0, 0, 1)
+	case len(fields) == 1 || (len(fields) == 2 && fields[0] == "last"):
+		wd, ok := weekdays[fields[len(fields)-1]]
+		if !ok {
+			return time.Time{}, fmt.Errorf("unrecognized time expression %q", expr)
+		}
+		day = lastWeekday(now, wd)
+	default:
+		return time.Time{}, fmt.Errorf("unrecognized time expression %q", expr)
+	}
+
+	if clock == "" {
+		return day, nil
+	}
+
+	t, err := applyClock(day, clock)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time of day in %q: %w", expr, err)
+	}
+	return t, nil
+}
+
+// ParseRange parses optional since/until expressions and checks their order
+func ParseRange(since, until string, now time.Time, loc *time.Location) (time.Time, time.Time, error) {
+	var start, end time.Time
+	var err error
+
+	if since != "" {
+		if start, err = Parse(since, now, loc); err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid --since: %w", err)
+		}
+	}
+	if until != "" {
+		if end, err = Parse(until, now, loc); err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid --until: %w", err)
+		}
+	}
+
+	if !start.IsZero() && !end.IsZero() && end.Before(start) {
+		return time.Time{}, time.Time{}, fmt.Errorf("--until (%s) is before --since (%s)",
+			end.Format(time.RFC3339), start.Format(time.RFC3339))
+	}
+
+	return start, end, nil
+}
+
+// parseDuration parses durations with day and week units in addition to Go's
+func parseDuration(s string) (time.Duration, bool) {
+	s = strings.ReplaceAll(s, " ", "")
+	if s == "" {
+		return 0, false
+	}
+
+	matches := durationPart.FindAllStringSubmatchIndex(s, -1)
+	var total time.Duration
+	pos := 0
+	for _, m := range matches {
+		if m[0] != pos {
+			return 0, false
+		}
+		pos = m[1]
+
+		value, err := strconv.ParseFloat(s[m[2]:m[3]], 64)
+		if err != nil {
+			return 0, false
+		}
+
+		var unit time.Duration
+		switch s[m[4]:m[5]] {
+		case "ms":
+			unit = time.Millisecond
+		case "s":
+			unit = time.Second
+		case "m":
+			unit = time.Minute
+		case "h":
+			unit = time.Hour
+		case "d":
+			unit = 24 * time.Hour
+		case "w":
+			unit = 7 * 24 * time.Hour
+		}
+		total += time.Duration(value * float64(unit))
+	}
+
+	return total, pos == len(s) && len(matches) > 0
+}
+
+// midnight returns the start of t's day in t's location
+func midnight(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+// lastWeekday returns midnight of the most recent wd strictly before today
+func lastWeekday(now time.Time, wd time.Weekday) time.Time {
+	days := int(now.Weekday() - wd)
+	if days <= 0 {
+		days += 7
+	}
+	return midnight(now).AddDate(0, 0, -days)
+}
+
+// applyClock sets an "HH:MM" or "HH:MM:SS" clock time on a day
+func applyClock(day time.Time, clock string) (time.Time, error) {
+	for _, layout := range []string{"15:04:05", "15:04"} {
+		if c, err := time.Parse(layout, clock); err == nil {
+			return time.Date(day.Year(), day.Month(), day.Day(),
+				c.Hour(), c.Minute(), c.Second(), 0, day.Location()), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("expected HH:MM or HH:MM:SS, got %q", clock)
+}
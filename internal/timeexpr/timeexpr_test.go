@@ -0,0 +1,142 @@
+package timeexpr
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	// Friday 2024-01-19 20:00 UTC, which is already Saturday 01:30 at +05:30
+	now := time.Date(2024, 1, 19, 20, 0, 0, 0, time.UTC)
+	kolkata := time.FixedZone("+05:30", 5*3600+30*60)
+
+	tests := []struct {
+		expr string
+		loc  *time.Location
+		want time.Time
+	}{
+		// Absolute
+		{"2024-01-15T10:00:00Z", time.UTC, time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)},
+		{"2024-01-15T10:00:00+02:00", kolkata, time.Date(2024, 1, 15, 8, 0, 0, 0, time.UTC)},
+		{"2024-01-15 10:00:00", kolkata, time.Date(2024, 1, 15, 10, 0, 0, 0, kolkata)},
+		{"2024-01-15", time.UTC, time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)},
+		{"2024-01-15 09:00", time.UTC, time.Date(2024, 1, 15, 9, 0, 0, 0, time.UTC)},
+		{"2024-01-15 09:00", kolkata, time.Date(2024, 1, 15, 9, 0, 0, 0, kolkata)},
+		{"2024-01-15 09:00:30", kolkata, time.Date(2024, 1, 15, 9, 0, 30, 0, kolkata)},
+
+		// Relative durations
+		{"now", time.UTC, now},
+		{"1h", time.UTC, now.Add(-time.Hour)},
+		{"90m", time.UTC, now.Add(-90 * time.Minute)},
+		{"1h30m", time.UTC, now.Add(-90 * time.Minute)},
+		{"2d", time.UTC, now.Add(-48 * time.Hour)},
+		{"1w", time.UTC, now.Add(-7 * 24 * time.Hour)},
+		{"1.5h", time.UTC, now.Add(-90 * time.Minute)},
+		{"3h ago", time.UTC, now.Add(-3 * time.Hour)},
+
+		// Days in UTC
+		{"today", time.UTC, time.Date(2024, 1, 19, 0, 0, 0, 0, time.UTC)},
+		{"yesterday", time.UTC, time.Date(2024, 1, 18, 0, 0, 0, 0, time.UTC)},
+		{"tomorrow", time.UTC, time.Date(2024, 1, 20, 0, 0, 0, 0, time.UTC)},
+		{"yesterday 09:00", time.UTC, time.Date(2024, 1, 18, 9, 0, 0, 0, time.UTC)},
+		{"15:04", time.UTC, time.Date(2024, 1, 19, 15, 4, 0, 0, time.UTC)},
+
+		// Days in a zone where the date differs from UTC
+		{"today", kolkata, time.Date(2024, 1, 20, 0, 0, 0, 0, kolkata)},
+		{"yesterday", kolkata, time.Date(2024, 1, 19, 0, 0, 0, 0, kolkata)},
+		{"yesterday 23:30", kolkata, time.Date(2024, 1, 19, 23, 30, 0, 0, kolkata)},
+
+		// Weekdays: the most recent one strictly before today
+		{"friday", time.UTC, time.Date(2024, 1, 12, 0, 0, 0, 0, time.UTC)},
+		{"thursday", time.UTC, time.Date(2024, 1, 18, 0, 0, 0, 0, time.UTC)},
+		{"last monday", time.UTC, time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)},
+		{"last monday 09:00", time.UTC, time.Date(2024, 1, 15, 9, 0, 0, 0, time.UTC)},
+		{"Sat", time.UTC, time.Date(2024, 1, 13, 0, 0, 0, 0, time.UTC)},
+		{"friday", kolkata, time.Date(2024, 1, 19, 0, 0, 0, 0, kolkata)}, // Saturday there
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr+" in "+tt.loc.String(), func(t *testing.T) {
+			got, err := Parse(tt.expr, now, tt.loc)
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.expr, err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("Parse(%q) = %s, want %s", tt.expr, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	now := time.Date(2024, 1, 19, 20, 0, 0, 0, time.UTC)
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{"", "empty time expression"},
+		{"   ", "empty time expression"},
+		{"next week", `unrecognized time expression "next week"`},
+		{"someday", `unrecognized time expression "someday"`},
+		{"last tomorrow", `unrecognized time expression "last tomorrow"`},
+		{"1x", `unrecognized time expression "1x"`},
+		{"yesterday 25:00", `invalid time of day in "yesterday 25:00"`},
+		{"2024-01-15 9am", `unrecognized time expression "2024-01-15 9am"`},
+	}
+
+	for _, tt := range tests {
+		_, err := Parse(tt.expr, now, time.UTC)
+		if err == nil {
+			t.Errorf("Parse(%q) succeeded, want an error", tt.expr)
+			continue
+		}
+		if !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Parse(%q) error = %q, want it to contain %q", tt.expr, err, tt.want)
+		}
+	}
+}
+
+func TestParseRange(t *testing.T) {
+	now := time.Date(2024, 1, 19, 20, 0, 0, 0, time.UTC)
+	start, end, err := ParseRange("yesterday", "1h", now, time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !start.Equal(time.Date(2024, 1, 18, 0, 0, 0, 0, time.UTC)) || !end.Equal(now.Add(-time.Hour)) {
+		t.Errorf("ParseRange = %s, %s", start, end)
+	}
+
+	if _, _, err := ParseRange("1h", "yesterday", now, time.UTC); err == nil || !strings.Contains(err.Error(), "is before --since") {
+		t.Errorf("reversed range error = %v, want an ordering error", err)
+	}
+	if _, _, err := ParseRange("bogus", "", now, time.UTC); err == nil || !strings.HasPrefix(err.Error(), "invalid --since: ") {
+		t.Errorf("invalid since error = %v", err)
+	}
+}
+
+func TestLoadLocation(t *testing.T) {
+	tests := []struct {
+		name   string
+		offset int // Seconds east of UTC, in January
+	}{
+		{"UTC", 0},
+		{"+05:30", 5*3600 + 30*60},
+		{"-0800", -8 * 3600},
+		{"Europe/Berlin", 3600},
+	}
+	january := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	for _, tt := range tests {
+		loc, err := LoadLocation(tt.name)
+		if err != nil {
+			t.Errorf("LoadLocation(%q): %v", tt.name, err)
+			continue
+		}
+		if _, offset := january.In(loc).Zone(); offset != tt.offset {
+			t.Errorf("LoadLocation(%q) offset = %d, want %d", tt.name, offset, tt.offset)
+		}
+	}
+	if _, err := LoadLocation("Mars/Olympus"); err == nil {
+		t.Errorf("LoadLocation of an unknown zone succeeded")
+	}
+}
@@ -0,0 +1,75 @@
+package watcher
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/aadithyaa9/loganalyzer/internal/alert"
+	"github.com/aadithyaa9/loganalyzer/internal/models"
+	"github.com/fatih/color"
+)
+
+// observeAlerts feeds an entry to the alert rules; entries of the backlog
+// read at startup do not count toward windows measured by read time
+func (w *Watcher) observeAlerts(entry *models.LogEntry) {
+	if w.config.Alerts == nil {
+		return
+	}
+	if w.backlog {
+		w.raise(w.config.Alerts.ObserveBacklog(entry, time.Now()))
+		return
+	}
+	w.raise(w.config.Alerts.Observe(entry, time.Now()))
+}
+
+// evaluateAlerts raises the alerts due to time passing
+func (w *Watcher) evaluateAlerts() {
+	if w.config.Alerts == nil {
+		return
+	}
+	w.raise(w.config.Alerts.Evaluate(time.Now()))
+}
+
+// raise shows alerts after the entries read before them, and logs them
+func (w *Watcher) raise(events []alert.Event) {
+	if len(events) == 0 {
+		return
+	}
+	for _, entry := range w.reorder.Drain() {
+		w.displayEntry(entry)
+	}
+
+	for _, event := range events {
+		// A failing alert log must not stop the watch
+		if w.alertEnc != nil {
+			_ = w.alertEnc.Encode(event)
+		}
+		w.displayAlert(event)
+	}
+}
+
+// displayAlert displays an alert with its sample lines
+func (w *Watcher) displayAlert(event alert.Event) {
+	name := event.Rule
+	if event.Source != "" {
+		name += " (" + event.Source + ")"
+	}
+	summary := event.Summary
+	if event.Suppressed > 0 {
+		summary += fmt.Sprintf(", %d duplicates suppressed", event.Suppressed)
+	}
+
+	if event.State == alert.Resolved {
+		color.New(color.FgGreen, color.Bold).Printf("✅ RESOLVED %s: %s\n", name, summary)
+	} else {
+		color.New(color.FgRed, color.Bold).Printf("🚨 ALERT %s: %s\n", name, summary)
+	}
+	for _, sample := range event.Samples {
+		fmt.Printf("   %s %-5s %s %s\n",
+			color.New(color.FgHiBlack).Sprintf("[%s]", sample.Timestamp.Format("15:04:05")),
+			sample.Level,
+			sample.Message,
+			color.New(color.FgHiBlack).Sprint(sample.Location),
+		)
+	}
+}
@@ -0,0 +1,85 @@
+package watcher
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/aadithyaa9/loganalyzer/internal/input"
+)
+
+// isGlob reports whether a path contains glob metacharacters
+func isGlob(path string) bool {
+	return strings.ContainsAny(path, "*?[")
+}
+
+// globRoot returns the deepest directory of a glob without metacharacters
+func globRoot(pattern string) string {
+	dir := filepath.Dir(pattern)
+	for isGlob(dir) {
+		dir = filepath.Dir(dir)
+	}
+	return dir
+}
+
+// expandPaths resolves the watched paths into the files to follow and the
+// directories to watch for new files. Directories are searched recursively
+// for live log files; globs match any file. Paths that do not exist (yet)
+// are skipped.
+func expandPaths(paths []string) (files, dirs []string) {
+	seenFiles := make(map[string]bool)
+	seenDirs := make(map[string]bool)
+	addFile := func(path string) {
+		if !seenFiles[path] {
+			seenFiles[path] = true
+			files = append(files, path)
+		}
+	}
+	addDir := func(path string) {
+		if !seenDirs[path] {
+			seenDirs[path] = true
+			dirs = append(dirs, path)
+		}
+	}
+
+	for _, path := range paths {
+		path = filepath.Clean(path)
+		if isGlob(path) {
+			if _, err := os.Stat(globRoot(path)); err == nil {
+				addDir(globRoot(path))
+			}
+			matches, _ := filepath.Glob(path) // Only fails on a malformed pattern
+			for _, match := range matches {
+				if info, err := os.Stat(match); err == nil && info.Mode().IsRegular() {
+					addFile(match)
+				}
+			}
+			continue
+		}
+
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		if !info.IsDir() {
+			addFile(path)
+			continue
+		}
+		filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return nil // Unreadable entries are skipped, not fatal
+			}
+			if d.IsDir() {
+				addDir(p)
+			} else if d.Type().IsRegular() && input.IsLiveLogFile(p) {
+				addFile(p)
+			}
+			return nil
+		})
+	}
+
+	sort.Strings(files)
+	return files, dirs
+}
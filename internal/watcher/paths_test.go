@@ -0,0 +1,130 @@
+package watcher
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+func TestExpandPaths(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"api/app.log", "worker/app.log", "worker/app.log.1", "worker/app.log.2.gz", "notes.txt"} {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		appendLog(t, path, "")
+	}
+
+	tests := []struct {
+		name  string
+		paths []string
+		files []string
+		dirs  []string
+	}{
+		{"directory", []string{dir}, []string{"api/app.log", "worker/app.log"}, []string{"", "api", "worker"}},
+		{"glob", []string{filepath.Join(dir, "*", "app.log*")}, []string{"api/app.log", "worker/app.log", "worker/app.log.1", "worker/app.log.2.gz"}, []string{""}},
+		{"file given twice", []string{filepath.Join(dir, "api/app.log"), filepath.Join(dir, "api", "..", "api", "app.log")}, []string{"api/app.log"}, nil},
+		{"missing path", []string{filepath.Join(dir, "missing.log")}, nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files, dirs := expandPaths(tt.paths)
+			if got := relative(t, dir, files); !slices.Equal(got, tt.files) {
+				t.Errorf("files = %q, want %q", got, tt.files)
+			}
+			if got := relative(t, dir, dirs); !slices.Equal(got, tt.dirs) {
+				t.Errorf("dirs = %q, want %q", got, tt.dirs)
+			}
+		})
+	}
+}
+
+// relative returns paths relative to dir, with "" for dir itself
+func relative(t *testing.T, dir string, paths []string) []string {
+	t.Helper()
+	var result []string
+	for _, path := range paths {
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rel == "." {
+			rel = ""
+		}
+		result = append(result, filepath.ToSlash(rel))
+	}
+	return result
+}
+
+func TestDiscoverSameBaseName(t *testing.T) {
+	dir := t.TempDir()
+	api := filepath.Join(dir, "api", "app.log")
+	worker := filepath.Join(dir, "worker", "app.log")
+	for _, path := range []string{api, worker} {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	appendLog(t, api, "2024-01-15 10:00:00 INFO from api\n")
+
+	w := NewWatcher(&Config{Paths: []string{dir}, ShowAll: true})
+	fsWatcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fsWatcher.Close()
+	w.fsWatcher = fsWatcher
+	defer w.closeAll()
+
+	if err := w.discover(true); err != nil {
+		t.Fatal(err)
+	}
+	if got := sources(w); !slices.Equal(got, []string{"app.log"}) {
+		t.Fatalf("sources after the first discovery = %q, want app.log", got)
+	}
+	for _, d := range []string{dir, filepath.Dir(api), filepath.Dir(worker)} {
+		if !w.dirs[d] {
+			t.Errorf("directory %s not watched", d)
+		}
+	}
+
+	// A file of the same name appears in another directory: both files are
+	// followed, under names that tell them apart
+	w.reorder.window = time.Hour
+	appendLog(t, worker, "2024-01-15 10:00:01 INFO from worker\n")
+	if err := w.discover(false); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := sources(w), []string{"api/app.log", "worker/app.log"}; !slices.Equal(got, want) {
+		t.Errorf("sources = %q, want %q", got, want)
+	}
+	if w.width != len("worker/app.log") {
+		t.Errorf("width = %d, want %d", w.width, len("worker/app.log"))
+	}
+	entries := shown(w)
+	if len(entries) != 1 || entries[0].Message != "from worker" || entries[0].Source != "worker/app.log" {
+		t.Errorf("new file showed %+v, want its line from worker/app.log", entries)
+	}
+
+	// Discovering again follows nothing twice
+	if err := w.discover(false); err != nil {
+		t.Fatal(err)
+	}
+	if len(w.tails) != 2 {
+		t.Errorf("following %d files, want 2", len(w.tails))
+	}
+}
+
+// sources returns the source names of the followed files, in order
+func sources(w *Watcher) []string {
+	var names []string
+	for _, t := range w.tails {
+		names = append(names, t.source)
+	}
+	return names
+}
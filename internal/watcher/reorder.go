@@ -0,0 +1,70 @@
+package watcher
+
+import (
+	"container/heap"
+	"time"
+
+	"github.com/aadithyaa9/loganalyzer/internal/models"
+)
+
+// reorderBuffer holds entries for a short window so entries read from
+// several files are displayed in timestamp order rather than read order
+type reorderBuffer struct {
+	window  time.Duration
+	pending pendingHeap
+	seq     int // Read order, to keep ties and each file's order stable
+}
+
+// pendingEntry is an entry waiting in the reorder buffer
+type pendingEntry struct {
+	entry *models.LogEntry
+	read  time.Time
+	seq   int
+}
+
+// Push adds an entry read at now
+func (b *reorderBuffer) Push(entry *models.LogEntry, now time.Time) {
+	heap.Push(&b.pending, pendingEntry{entry: entry, read: now, seq: b.seq})
+	b.seq++
+}
+
+// Pop returns the earliest entry once it has waited out the window
+func (b *reorderBuffer) Pop(now time.Time) (*models.LogEntry, bool) {
+	if len(b.pending) == 0 || now.Sub(b.pending[0].read) < b.window {
+		return nil, false
+	}
+	return heap.Pop(&b.pending).(pendingEntry).entry, true
+}
+
+// Drain returns every pending entry, earliest first
+func (b *reorderBuffer) Drain() []*models.LogEntry {
+	entries := make([]*models.LogEntry, 0, len(b.pending))
+	for len(b.pending) > 0 {
+		entries = append(entries, heap.Pop(&b.pending).(pendingEntry).entry)
+	}
+	return entries
+}
+
+// pendingHeap is a min-heap on timestamp, then read order
+type pendingHeap []pendingEntry
+
+func (h pendingHeap) Len() int { return len(h) }
+func (h pendingHeap) Less(i, j int) bool {
+	if !h[i].entry.Timestamp.Equal(h[j].entry.Timestamp) {
+		return h[i].entry.Timestamp.Before(h[j].entry.Timestamp)
+	}
+	return h[i].seq < h[j].seq
+}
+func (h pendingHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *pendingHeap) Push(x any) {
+	*h = append(*h, x.(pendingEntry))
+}
+
+func (h *pendingHeap) Pop() any {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	*h = old[:n-1]
+	return x
+}
@@ -0,0 +1,64 @@
+package watcher
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/aadithyaa9/loganalyzer/internal/models"
+)
+
+func TestReorderBuffer(t *testing.T) {
+	start := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	b := reorderBuffer{window: 2 * time.Second}
+	push := func(message string, logged, read time.Duration) {
+		b.Push(&models.LogEntry{Timestamp: start.Add(logged), Message: message}, start.Add(read))
+	}
+	pop := func(now time.Duration) []string {
+		var popped []string
+		for {
+			entry, ok := b.Pop(start.Add(now))
+			if !ok {
+				return popped
+			}
+			popped = append(popped, entry.Message)
+		}
+	}
+
+	// Read from two files out of timestamp order
+	push("api 1", 1*time.Second, 0)
+	push("worker 0", 0, 500*time.Millisecond)
+	push("api 2", 2*time.Second, 1*time.Second)
+	push("worker 2", 2*time.Second, 1500*time.Millisecond) // Same timestamp, read later
+
+	// The earliest entry waits out its own window, holding back the
+	// entries logged after it even when they were read before it
+	if got := pop(2 * time.Second); len(got) != 0 {
+		t.Errorf("Pop at 2s = %q, want nothing", got)
+	}
+	if got, want := pop(2500*time.Millisecond), []string{"worker 0", "api 1"}; !slices.Equal(got, want) {
+		t.Errorf("Pop at 2.5s = %q, want %q", got, want)
+	}
+	if got, want := pop(3*time.Second), []string{"api 2"}; !slices.Equal(got, want) {
+		t.Errorf("Pop at 3s = %q, want %q", got, want)
+	}
+
+	// An entry logged earlier but read late goes ahead of the entries
+	// still pending, which then wait for it
+	push("late", 500*time.Millisecond, 3*time.Second)
+	if got := pop(4 * time.Second); len(got) != 0 {
+		t.Errorf("Pop at 4s = %q, want nothing", got)
+	}
+	if got, want := pop(5*time.Second), []string{"late", "worker 2"}; !slices.Equal(got, want) {
+		t.Errorf("Pop at 5s = %q, want %q", got, want)
+	}
+
+	push("b", time.Second, 4*time.Second)
+	push("a", 0, 4*time.Second)
+	if got, want := b.Drain(), []string{"a", "b"}; !slices.Equal(messages(got), want) {
+		t.Errorf("Drain = %q, want %q", messages(got), want)
+	}
+	if got := pop(time.Hour); len(got) != 0 {
+		t.Errorf("Pop after Drain = %q, want nothing", got)
+	}
+}
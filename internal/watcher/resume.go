@@ -0,0 +1,82 @@
+package watcher
+
+import (
+	"fmt"
+
+	"github.com/aadithyaa9/loganalyzer/internal/checkpoint"
+	"github.com/aadithyaa9/loganalyzer/internal/input"
+	"github.com/fatih/color"
+)
+
+// lookupCheckpoint returns where a previous run stopped reading a file
+func (w *Watcher) lookupCheckpoint(path string) (checkpoint.Checkpoint, bool) {
+	if w.config.Checkpoints == nil {
+		return checkpoint.Checkpoint{}, false
+	}
+	return w.config.Checkpoints.Lookup(path)
+}
+
+// drainRenamed shows the lines still unread in the file path named at the
+// last checkpoint, when that file was rotated away while the watcher was
+// stopped. The rotated file may have been compressed since.
+func (w *Watcher) drainRenamed(path string) {
+	if w.config.Checkpoints == nil {
+		return
+	}
+	old, ok := w.config.Checkpoints.Renamed(path)
+	if !ok {
+		return
+	}
+	c, ok := w.config.Checkpoints.Lookup(old)
+	if !ok {
+		return
+	}
+
+	file, err := input.Open(old)
+	if err != nil {
+		return
+	}
+	defer file.Close()
+	if err := file.Skip(c.Offset); err != nil {
+		return
+	}
+
+	w.backlog = true
+	defer func() { w.backlog = false }()
+
+	t := w.newTail(old)
+	w.notice(t, fmt.Sprintf("holds lines written after %s was last read", path))
+	scanner := input.NewLineScanner(file, c.Position())
+	for scanner.Scan() {
+		if record, ok := t.assembler.Add(scanner.Text(), scanner.Position()); ok {
+			w.processLine(t, record)
+		}
+	}
+	w.flushPending(t)
+
+	if c, err := checkpoint.TakePath(old, scanner.Next()); err == nil {
+		w.config.Checkpoints.Set(old, c)
+	}
+}
+
+// saveCheckpoints records how far every followed file was read. Lines
+// still buffered for an incomplete entry are not done, so the checkpoint
+// is where the first of them starts.
+func (w *Watcher) saveCheckpoints() {
+	if w.config.Checkpoints == nil {
+		return
+	}
+	for _, t := range w.tails {
+		pos := t.next
+		if start, ok := t.assembler.PendingStart(); ok {
+			pos = start
+		}
+		if c, err := checkpoint.Take(t.file, pos); err == nil {
+			w.config.Checkpoints.Set(t.path, c)
+		}
+	}
+	if err := w.config.Checkpoints.Save(); err != nil && !w.saveFailed {
+		w.saveFailed = true
+		color.Yellow("⚠️  Failed to save checkpoints: %v", err)
+	}
+}
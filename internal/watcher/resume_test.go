@@ -0,0 +1,81 @@
+package watcher
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/aadithyaa9/loganalyzer/internal/checkpoint"
+	"github.com/aadithyaa9/loganalyzer/internal/models"
+)
+
+func TestResumeMidEntry(t *testing.T) {
+	tests := []struct {
+		name    string
+		before  string   // Written before the checkpoint is saved
+		after   string   // Written after the restart
+		shown   []string // By the first run
+		resumed []string // By the second run, which completes the cut entry
+	}{
+		{
+			name:    "multiline entry",
+			before:  "2024-01-15 10:00:00 INFO started\n2024-01-15 10:00:01 ERROR boom\n\tat A.a(A.java:1)\n",
+			after:   "\tat B.b(B.java:2)\n2024-01-15 10:00:02 INFO next\n",
+			shown:   []string{"started"},
+			resumed: []string{"boom\n\tat A.a(A.java:1)\n\tat B.b(B.java:2)", "next"},
+		},
+		{
+			name:    "line still being written",
+			before:  "2024-01-15 10:00:00 INFO started\n2024-01-15 10:00:01 WARN half",
+			after:   "way there\n2024-01-15 10:00:02 INFO next\n",
+			shown:   []string{},
+			resumed: []string{"started", "halfway there", "next"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, "app.log")
+			statePath := filepath.Join(dir, "state.json")
+			appendLog(t, path, tt.before)
+
+			// First run: reads, saves and stops without flushing, as a crash would
+			store, err := checkpoint.Load(statePath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			w, tl := followForTest(t, &Config{Multiline: true, Checkpoints: store}, path)
+			w.read(tl, false)
+			if got := messages(shown(w)); !slices.Equal(got, tt.shown) {
+				t.Fatalf("first run showed %q, want %q", got, tt.shown)
+			}
+			w.saveCheckpoints()
+
+			// Second run resumes from the saved state
+			appendLog(t, path, tt.after)
+			store, err = checkpoint.Load(statePath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			w, tl = followForTest(t, &Config{Multiline: true, Checkpoints: store}, path)
+			if !tl.resumed {
+				t.Fatalf("second run did not resume")
+			}
+			w.read(tl, false)
+			w.flushPending(tl)
+			if got := messages(shown(w)); !slices.Equal(got, tt.resumed) {
+				t.Errorf("second run showed %q, want %q", got, tt.resumed)
+			}
+		})
+	}
+}
+
+// messages lists the messages of entries
+func messages(entries []*models.LogEntry) []string {
+	result := make([]string, len(entries))
+	for i, entry := range entries {
+		result[i] = entry.Message
+	}
+	return result
+}
@@ -0,0 +1,229 @@
+package watcher
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/aadithyaa9/loganalyzer/internal/models"
+	"github.com/aadithyaa9/loganalyzer/internal/templates"
+	"github.com/fatih/color"
+)
+
+// statsWindows are the sliding windows of the periodic stats
+var statsWindows = []time.Duration{time.Minute, 5 * time.Minute, 15 * time.Minute}
+
+// statsHorizon is the number of one-second buckets kept, enough for the
+// longest window
+const statsHorizon = 15 * 60
+
+// statsLevels are the levels shown in the stats, most severe first, then
+// entries of structured formats that named no level
+var statsLevels = []models.LogLevel{models.FATAL, models.ERROR, models.WARN, models.INFO, models.DEBUG, models.UNKNOWN}
+
+// statsTopTemplates is how many templates the stats list
+const statsTopTemplates = 5
+
+// liveStats counts the entries matched while watching, by the second they
+// were read, and mines their error templates. It is only used from the
+// watch loop, so it needs no locking.
+type liveStats struct {
+	started time.Time
+	buckets [statsHorizon]statsBucket
+	total   *models.Statistics // Since the start
+	miner   *templates.Miner
+
+	lastReport time.Time
+	reported   int // Total entries at the last report
+	known      int // Templates created by the last report
+}
+
+// statsBucket holds the counts of one second
+type statsBucket struct {
+	second  int64
+	levels  map[models.LogLevel]int
+	sources map[string]int
+}
+
+// newLiveStats creates empty stats starting at now
+func newLiveStats(now time.Time) *liveStats {
+	return &liveStats{
+		started:    now,
+		total:      models.NewStatistics(),
+		miner:      templates.NewMiner(),
+		lastReport: now,
+	}
+}
+
+// Add counts an entry read at now
+func (s *liveStats) Add(entry *models.LogEntry, now time.Time) {
+	second := now.Unix()
+	b := &s.buckets[second%statsHorizon]
+	if b.second != second || b.levels == nil {
+		*b = statsBucket{
+			second:  second,
+			levels:  make(map[models.LogLevel]int),
+			sources: make(map[string]int),
+		}
+	}
+	b.levels[entry.Level]++
+	b.sources[entry.Source]++
+
+	s.total.AddEntry(entry)
+	if entry.Level == models.ERROR || entry.Level == models.FATAL {
+		s.miner.Add(entry.Message)
+	}
+}
+
+// window sums the counts of the entries read within d before now
+func (s *liveStats) window(d time.Duration, now time.Time) (map[models.LogLevel]int, map[string]int) {
+	levels := make(map[models.LogLevel]int)
+	sources := make(map[string]int)
+	oldest := now.Unix() - int64(d/time.Second)
+	for i := range s.buckets {
+		b := &s.buckets[i]
+		if b.levels == nil || b.second <= oldest || b.second > now.Unix() {
+			continue
+		}
+		for level, n := range b.levels {
+			levels[level] += n
+		}
+		for source, n := range b.sources {
+			sources[source] += n
+		}
+	}
+	return levels, sources
+}
+
+// Report prints the sliding-window counts, the rate since the previous
+// report and the templates first seen since then
+func (s *liveStats) Report(now time.Time) {
+	elapsed := now.Sub(s.lastReport)
+	count := s.total.TotalEntries - s.reported
+
+	rule := color.New(color.FgCyan).Sprint("───────────────────────────────────────────────")
+	fmt.Println(rule)
+	fmt.Printf("📊 Stats at %s: %d entries in the last %s (%.1f/s)\n",
+		now.Format("15:04:05"), count, elapsed.Round(time.Second), rate(count, elapsed))
+
+	levels := make([]map[models.LogLevel]int, len(statsWindows))
+	sources := make([]map[string]int, len(statsWindows))
+	for i, d := range statsWindows {
+		levels[i], sources[i] = s.window(d, now)
+	}
+
+	// Sources seen in the longest window, busiest first
+	names := make([]string, 0, len(sources[len(sources)-1]))
+	for name := range sources[len(sources)-1] {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		last := sources[len(sources)-1]
+		if last[names[i]] != last[names[j]] {
+			return last[names[i]] > last[names[j]]
+		}
+		return names[i] < names[j]
+	})
+
+	width := 5
+	for _, name := range names {
+		width = max(width, len(name))
+	}
+	header := fmt.Sprintf("   %-*s", width, "")
+	for _, d := range statsWindows {
+		header += fmt.Sprintf(" %7s", formatWindow(d))
+	}
+	fmt.Println(color.New(color.FgHiBlack).Sprint(header))
+	for _, level := range statsLevels {
+		fmt.Printf("   %-*s", width, level)
+		for i := range statsWindows {
+			fmt.Printf(" %7d", levels[i][level])
+		}
+		fmt.Println()
+	}
+	for _, name := range names {
+		fmt.Printf("   %-*s", width, name)
+		for i := range statsWindows {
+			fmt.Printf(" %7d", sources[i][name])
+		}
+		fmt.Println()
+	}
+
+	if fresh := s.newTemplates(); len(fresh) > 0 {
+		fmt.Println("🆕 New error templates")
+		for i, c := range fresh {
+			fmt.Printf("   %d. %s  %s\n", i+1, c.Template(), color.New(color.FgYellow).Sprintf("x %d", c.Count))
+		}
+	}
+	fmt.Println(rule)
+
+	s.lastReport = now
+	s.reported = s.total.TotalEntries
+	s.known = s.miner.Created()
+}
+
+// newTemplates returns the most frequent templates mined since the last report
+func (s *liveStats) newTemplates() []templates.Cluster {
+	var fresh []templates.Cluster
+	for _, c := range s.miner.Clusters() {
+		if c.ID > s.known {
+			fresh = append(fresh, c)
+		}
+		if len(fresh) == statsTopTemplates {
+			break
+		}
+	}
+	return fresh
+}
+
+// Summary prints the totals since the start
+func (s *liveStats) Summary(now time.Time) {
+	elapsed := now.Sub(s.started)
+	rule := color.New(color.FgCyan).Sprint("───────────────────────────────────────────────")
+	fmt.Println(rule)
+	fmt.Printf("📊 Watched for %s: %d entries (%.1f/s)\n",
+		elapsed.Round(time.Second), s.total.TotalEntries, rate(s.total.TotalEntries, elapsed))
+
+	fmt.Print("   ")
+	for i, level := range statsLevels {
+		if i > 0 {
+			fmt.Print(" | ")
+		}
+		fmt.Printf("%s: %d", level, s.total.GetLevelCount(level))
+	}
+	fmt.Println()
+
+	names := make([]string, 0, len(s.total.SourceCounts))
+	for name := range s.total.SourceCounts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Printf("   %s: %d\n", name, s.total.SourceCounts[name])
+	}
+
+	s.total.SetPatternCounts(s.miner.Counts())
+	if patterns := s.total.TopPatterns(statsTopTemplates); len(patterns) > 0 {
+		fmt.Println("🔥 Top error templates")
+		for i, p := range patterns {
+			fmt.Printf("   %d. %s  %s\n", i+1, p.Pattern, color.New(color.FgYellow).Sprintf("x %d", p.Count))
+		}
+	}
+	fmt.Println(rule)
+}
+
+// rate returns count per second over elapsed
+func rate(count int, elapsed time.Duration) float64 {
+	if elapsed <= 0 {
+		return 0
+	}
+	return float64(count) / elapsed.Seconds()
+}
+
+// formatWindow formats a window length as "1m", "15m"
+func formatWindow(d time.Duration) string {
+	if d%time.Minute == 0 {
+		return fmt.Sprintf("%dm", d/time.Minute)
+	}
+	return d.String()
+}
@@ -0,0 +1,96 @@
+package watcher
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aadithyaa9/loganalyzer/internal/models"
+)
+
+func TestLiveStatsWindow(t *testing.T) {
+	start := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	s := newLiveStats(start)
+	add := func(level models.LogLevel, source string, after time.Duration) {
+		s.Add(&models.LogEntry{Level: level, Source: source, Message: "m"}, start.Add(after))
+	}
+	add(models.ERROR, "api.log", 0)
+	add(models.INFO, "api.log", 30*time.Second)
+	add(models.WARN, "worker.log", 4*time.Minute)
+	add(models.UNKNOWN, "worker.log", 10*time.Minute)
+	add(models.ERROR, "worker.log", 10*time.Minute+500*time.Millisecond) // Same second
+
+	tests := []struct {
+		window  time.Duration
+		at      time.Duration
+		levels  map[models.LogLevel]int
+		sources map[string]int
+	}{
+		{time.Minute, 30 * time.Second, map[models.LogLevel]int{models.ERROR: 1, models.INFO: 1}, map[string]int{"api.log": 2}},
+		// The window covers the last 60 seconds, so the entry read at 0s
+		// has expired at 60s
+		{time.Minute, 59 * time.Second, map[models.LogLevel]int{models.ERROR: 1, models.INFO: 1}, map[string]int{"api.log": 2}},
+		{time.Minute, 60 * time.Second, map[models.LogLevel]int{models.INFO: 1}, map[string]int{"api.log": 1}},
+		{time.Minute, 5 * time.Minute, nil, nil},
+		{5 * time.Minute, 5 * time.Minute, map[models.LogLevel]int{models.INFO: 1, models.WARN: 1}, map[string]int{"api.log": 1, "worker.log": 1}},
+		{15 * time.Minute, 10 * time.Minute, map[models.LogLevel]int{models.ERROR: 2, models.INFO: 1, models.WARN: 1, models.UNKNOWN: 1}, map[string]int{"api.log": 2, "worker.log": 3}},
+		{time.Minute, 10 * time.Minute, map[models.LogLevel]int{models.ERROR: 1, models.UNKNOWN: 1}, map[string]int{"worker.log": 2}},
+		// Entries read later than now are not counted
+		{15 * time.Minute, 2 * time.Minute, map[models.LogLevel]int{models.ERROR: 1, models.INFO: 1}, map[string]int{"api.log": 2}},
+	}
+	for _, tt := range tests {
+		levels, sources := s.window(tt.window, start.Add(tt.at))
+		if !equalCounts(levels, tt.levels) {
+			t.Errorf("%s window at %s: levels = %v, want %v", formatWindow(tt.window), tt.at, levels, tt.levels)
+		}
+		if !equalCounts(sources, tt.sources) {
+			t.Errorf("%s window at %s: sources = %v, want %v", formatWindow(tt.window), tt.at, sources, tt.sources)
+		}
+	}
+}
+
+func TestLiveStatsBucketReuse(t *testing.T) {
+	// A bucket is reused one horizon later; its old counts must not leak
+	// into the new second
+	start := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	s := newLiveStats(start)
+	s.Add(&models.LogEntry{Level: models.ERROR, Source: "old.log"}, start)
+	later := start.Add(statsHorizon * time.Second)
+	s.Add(&models.LogEntry{Level: models.INFO, Source: "new.log"}, later)
+
+	levels, sources := s.window(15*time.Minute, later)
+	if want := map[models.LogLevel]int{models.INFO: 1}; !equalCounts(levels, want) {
+		t.Errorf("levels = %v, want %v", levels, want)
+	}
+	if want := map[string]int{"new.log": 1}; !equalCounts(sources, want) {
+		t.Errorf("sources = %v, want %v", sources, want)
+	}
+	if s.total.TotalEntries != 2 {
+		t.Errorf("total = %d, want 2", s.total.TotalEntries)
+	}
+}
+
+func TestStatsLevelsCoverEveryLevel(t *testing.T) {
+	// Every entry is counted in one of the level rows
+	for level := models.DEBUG; level <= models.UNKNOWN; level++ {
+		found := false
+		for _, shown := range statsLevels {
+			found = found || shown == level
+		}
+		if !found {
+			t.Errorf("level %s has no stats row", level)
+		}
+	}
+}
+
+// equalCounts compares counts, treating nil and empty as equal
+func equalCounts[K comparable](got, want map[K]int) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	for key, n := range want {
+		if got[key] != n {
+			return false
+		}
+	}
+	return true
+}
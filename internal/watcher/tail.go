@@ -0,0 +1,266 @@
+package watcher
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/aadithyaa9/loganalyzer/internal/input"
+	"github.com/aadithyaa9/loganalyzer/internal/models"
+	"github.com/aadithyaa9/loganalyzer/internal/parser"
+	"github.com/fatih/color"
+)
+
+// sourceColors tell the sources of interleaved lines apart
+var sourceColors = []color.Attribute{
+	color.FgCyan, color.FgMagenta, color.FgBlue, color.FgHiGreen, color.FgHiYellow, color.FgHiCyan,
+}
+
+// tail is the read state of one followed file
+type tail struct {
+	path       string
+	source     string // Display name, unique among the followed files
+	color      *color.Color
+	file       *os.File
+	info       os.FileInfo // Identity (device and inode) of the open file
+	missing    bool        // The path is gone; the open file is read until it returns
+	lastOffset int64
+	next       models.Position // Position of the next line to read
+	selector   *parser.Selector
+	announced  parser.LogParser // Locked parser last reported to the user
+	assembler  *parser.RecordAssembler
+	container  models.Fields // Pod and container named by the file path
+	window     *models.ContextWindow
+	lastRead   time.Time // When a line was last read, to detect idle files
+	resumed    bool      // Whether reading continued from a checkpoint
+}
+
+// newTail creates the read state of a file, positioned at its start
+func (w *Watcher) newTail(path string) *tail {
+	t := &tail{
+		path:      path,
+		source:    filepath.Base(path),
+		color:     color.New(sourceColors[len(w.tails)%len(sourceColors)]),
+		next:      models.Position{Line: 1},
+		container: parser.ContainerPathFields(path),
+	}
+	if w.config.Parser != nil {
+		t.selector = parser.NewFixedSelector(w.config.Parser)
+		t.announced = w.config.Parser
+	} else {
+		t.selector = w.detector.NewSelector(parser.DefaultSampleLines)
+	}
+	var multiline *parser.MultilineAssembler
+	if w.config.Multiline {
+		multiline = parser.NewMultilineAssembler(w.config.MultilineStart)
+	}
+	t.assembler = parser.NewRecordAssembler(multiline)
+	if w.config.Before > 0 || w.config.After > 0 {
+		t.window = models.NewContextWindow(w.config.Before, w.config.After)
+	}
+	return t
+}
+
+// follow opens a file for tailing from its checkpoint, or without one from
+// its end (like tail -f) or its start
+func (w *Watcher) follow(path string, fromEnd bool) (*tail, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+
+	t := w.newTail(path)
+	t.file = file
+	t.info, err = file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to stat file: %w", err)
+	}
+
+	if c, ok := w.lookupCheckpoint(path); ok {
+		if _, err := file.Seek(c.Offset, io.SeekStart); err != nil {
+			file.Close()
+			return nil, fmt.Errorf("failed to seek: %w", err)
+		}
+		t.lastOffset = c.Offset
+		t.next = c.Position()
+		t.resumed = true
+		return t, nil
+	}
+
+	// Skip to the end without reading the skipped lines, so line numbers
+	// are unknown (0) from there
+	if fromEnd {
+		t.lastOffset, err = file.Seek(0, io.SeekEnd)
+		if err != nil {
+			file.Close()
+			return nil, fmt.Errorf("failed to seek: %w", err)
+		}
+		t.next = models.Position{Offset: t.lastOffset}
+	}
+	return t, nil
+}
+
+// readNewLines reads new lines added to a file, following it across
+// rotations like tail -F
+func (w *Watcher) readNewLines(t *tail) {
+	w.read(t, false)
+	if w.checkRotation(t) {
+		w.read(t, false)
+	}
+}
+
+// checkRotation reopens the path once it names a different file than the
+// one open (logrotate's rename and create). The old file is drained to its
+// end first, so lines written just before the rotation are not lost. While
+// the path is missing the old file keeps being read. It reports whether
+// the path was reopened.
+func (w *Watcher) checkRotation(t *tail) bool {
+	info, err := os.Stat(t.path)
+	if err != nil {
+		if !t.missing {
+			t.missing = true
+			w.notice(t, "is gone, waiting for it to be recreated")
+		}
+		return false
+	}
+	t.missing = false
+	if os.SameFile(t.info, info) {
+		return false
+	}
+
+	file, err := os.Open(t.path)
+	if err != nil {
+		return false // Retried on the next check
+	}
+	info, err = file.Stat()
+	if err != nil {
+		file.Close()
+		return false
+	}
+
+	w.read(t, true)
+	w.flushPending(t)
+	t.file.Close()
+
+	t.file = file
+	t.info = info
+	t.lastOffset = 0
+	t.next = models.Position{Line: 1}
+	w.notice(t, "was rotated, following the new file")
+	return true
+}
+
+// read reads the lines appended to the open file since the last read. A
+// last line without terminator is still being written and is read again
+// from its start next time, unless the file is final (rotated away).
+func (w *Watcher) read(t *tail, final bool) {
+	// Get current file size
+	fileInfo, err := t.file.Stat()
+	if err != nil {
+		return
+	}
+
+	currentSize := fileInfo.Size()
+
+	// Check if file was truncated in place (copytruncate)
+	if currentSize < t.lastOffset {
+		w.flushPending(t)
+		t.lastOffset = 0
+		t.next = models.Position{Line: 1}
+		t.file.Seek(0, io.SeekStart)
+		w.notice(t, "was truncated, reading from the start")
+	}
+
+	// Read new content
+	scanner := input.NewLineScanner(t.file, t.next)
+	for scanner.Scan() {
+		if scanner.Partial() && !final {
+			break
+		}
+		t.lastRead = time.Now()
+		if record, ok := t.assembler.Add(scanner.Text(), scanner.Position()); ok {
+			w.processLine(t, record)
+		}
+		t.next = scanner.Next()
+	}
+
+	// The scanner reads ahead; continue after the last line taken
+	t.lastOffset = t.next.Offset
+	t.file.Seek(t.lastOffset, io.SeekStart)
+}
+
+// notice reports a change to a followed file, after the entries read
+// before it
+func (w *Watcher) notice(t *tail, what string) {
+	for _, entry := range w.reorder.Drain() {
+		w.displayEntry(entry)
+	}
+	color.New(color.FgHiBlack).Printf("🔄 %s %s\n", t.path, what)
+}
+
+// flushPending processes entries and partial lines still held by the assembler
+func (w *Watcher) flushPending(t *tail) {
+	for {
+		record, ok := t.assembler.Flush()
+		if !ok {
+			return
+		}
+		w.processLine(t, record)
+	}
+}
+
+// processLine processes a single log record
+func (w *Watcher) processLine(t *tail, record parser.Record) {
+	if record.Text == "" {
+		return
+	}
+
+	// Parse line
+	entry, err := w.parse(t, record.Text)
+	if err != nil {
+		return
+	}
+	entry.Path = t.path
+	record.Apply(entry)
+	for key, value := range t.container {
+		if entry.Fields == nil {
+			entry.Fields = make(models.Fields, len(t.container))
+		}
+		entry.Fields[key] = value
+	}
+
+	w.observeAlerts(entry)
+
+	// Apply filters, keeping the entries around matches as context
+	match := w.shouldInclude(entry)
+	if match && w.stats != nil {
+		w.stats.Add(entry, time.Now())
+	}
+	if t.window == nil {
+		if match {
+			w.show(entry)
+		}
+		return
+	}
+	for _, e := range t.window.Add(entry, match) {
+		w.show(e)
+	}
+}
+
+// parse parses a line with the file's parser, reporting the format once
+// the selector has locked onto one
+func (w *Watcher) parse(t *tail, line string) (*models.LogEntry, error) {
+	entry, err := t.selector.Parse(line, t.source)
+	if locked := t.selector.Locked(); locked != nil && locked != t.announced {
+		t.announced = locked
+		if w.multi {
+			color.New(color.FgHiBlack).Printf("🧩 %s: detected format %s\n", t.source, locked.Name())
+		} else {
+			color.New(color.FgHiBlack).Printf("🧩 Detected format: %s\n", locked.Name())
+		}
+	}
+	return entry, err
+}
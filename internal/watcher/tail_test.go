@@ -0,0 +1,94 @@
+package watcher
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aadithyaa9/loganalyzer/internal/models"
+)
+
+// followForTest follows path from its start, holding the entries shown in
+// the reorder buffer so the test can collect them with shown
+func followForTest(t *testing.T, config *Config, path string) (*Watcher, *tail) {
+	t.Helper()
+	config.Paths = []string{path}
+	w := NewWatcher(config)
+	tl, err := w.follow(path, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { tl.file.Close() })
+	w.tails = append(w.tails, tl)
+	w.byPath[path] = tl
+	w.reorder.window = time.Hour
+	return w, tl
+}
+
+// shown returns the entries shown since the last call
+func shown(w *Watcher) []*models.LogEntry {
+	return w.reorder.Drain()
+}
+
+func appendLog(t *testing.T, path, content string) {
+	t.Helper()
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	if _, err := file.WriteString(content); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReadHoldsPartialLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	appendLog(t, path, "2024-01-15 10:00:00 INFO started\n2024-01-15 10:00:01 ERROR connection ref")
+	w, tl := followForTest(t, &Config{}, path)
+
+	w.read(tl, false)
+	entries := shown(w)
+	if len(entries) != 1 || entries[0].Message != "started" {
+		t.Fatalf("first read showed %v, want only the complete line", entries)
+	}
+	if want := int64(len("2024-01-15 10:00:00 INFO started\n")); tl.next.Offset != want || tl.lastOffset != want {
+		t.Errorf("next = %+v, lastOffset = %d, want both at %d", tl.next, tl.lastOffset, want)
+	}
+
+	// Nothing new: the partial line is still held
+	w.read(tl, false)
+	if entries := shown(w); len(entries) != 0 {
+		t.Errorf("second read showed %v, want nothing", entries)
+	}
+
+	appendLog(t, path, "used by peer\n")
+	w.read(tl, false)
+	entries = shown(w)
+	if len(entries) != 1 {
+		t.Fatalf("third read showed %d entries, want 1", len(entries))
+	}
+	if e := entries[0]; e.Message != "connection refused by peer" || e.Level != models.ERROR || e.Line != 2 {
+		t.Errorf("completed line = line %d %s %q, want line 2 ERROR %q", e.Line, e.Level, e.Message, "connection refused by peer")
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tl.next.Offset != info.Size() || tl.next.Line != 3 {
+		t.Errorf("next = %+v, want line 3 at %d", tl.next, info.Size())
+	}
+}
+
+func TestReadTakesPartialLineOfFinalFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	appendLog(t, path, "2024-01-15 10:00:00 INFO started\n2024-01-15 10:00:01 WARN last words")
+	w, tl := followForTest(t, &Config{}, path)
+
+	w.read(tl, true)
+	entries := shown(w)
+	if len(entries) != 2 || entries[1].Message != "last words" {
+		t.Fatalf("read of a final file showed %v, want both lines", entries)
+	}
+}
@@ -1,12 +1,19 @@
 package watcher
 
 import (
-	"bufio"
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"regexp"
 	"time"
 
+	"github.com/aadithyaa9/loganalyzer/internal/alert"
+	"github.com/aadithyaa9/loganalyzer/internal/checkpoint"
+	"github.com/aadithyaa9/loganalyzer/internal/input"
+	"github.com/aadithyaa9/loganalyzer/internal/matcher"
 	"github.com/aadithyaa9/loganalyzer/internal/models"
 	"github.com/aadithyaa9/loganalyzer/internal/parser"
 	"github.com/fatih/color"
@@ -15,150 +22,355 @@ import (
 
 // Config holds watcher configuration
 type Config struct {
-	FilePath string
-	Pattern  string
-	MinLevel models.LogLevel
-	Interval time.Duration
-	ShowAll  bool
+	// Paths are the files, directories and glob patterns to follow.
+	// Directories are searched recursively for live log files; files
+	// created later under a directory or matching a glob are picked up.
+	Paths []string
+
+	Pattern   string
+	Matcher   matcher.Matcher // Compiled Pattern; built from Pattern as a literal if nil
+	MinLevel  models.LogLevel
+	StartTime time.Time
+	EndTime   time.Time
+	Interval  time.Duration
+	ShowAll   bool
+
+	// Reorder holds entries this long so entries of several files are
+	// displayed in timestamp order; 0 displays them as they are read.
+	// A single file is always displayed as read.
+	Reorder time.Duration
+
+	// Multiline folds stack traces into the preceding entry; pending
+	// entries are flushed once the file has been idle for one Interval
+	Multiline      bool
+	MultilineStart *regexp.Regexp
+
+	// Parser forces one parser; otherwise each file samples its first
+	// lines and locks onto the best parser built from ParserOptions
+	Parser        parser.LogParser
+	ParserOptions parser.Options
+
+	// Before and After show that many entries preceding and following
+	// each match as context, like grep -B and -A
+	Before int
+	After  int
+
+	// Checkpoints resume each file where the previous run stopped reading
+	// it; they are saved every Interval and on exit
+	Checkpoints *checkpoint.Store
+
+	// StatsEvery prints sliding-window counts of the matched entries at
+	// this interval, and a summary on exit; 0 disables stats
+	StatsEvery time.Duration
+
+	// Alerts evaluates rules against every entry read, before the display
+	// filters; raised and resolved alerts are shown and, if AlertLog is
+	// set, written to it as one JSON object per line
+	Alerts   *alert.Engine
+	AlertLog io.Writer
 }
 
-// Watcher watches a log file for changes in real-time
+// Watcher watches log files for changes in real-time
 type Watcher struct {
-	config     *Config
-	parser     parser.LogParser
-	file       *os.File
-	lastOffset int64
+	config   *Config
+	detector *parser.Detector
+
+	tails     []*tail          // Followed files, in the order they were found
+	byPath    map[string]*tail // The same, by path
+	fsWatcher *fsnotify.Watcher
+	dirs      map[string]bool // Directories watched for changes
+
+	reorder   reorderBuffer
+	multi     bool // Whether lines are prefixed with their source
+	width     int  // Width of the longest source name
+	displayed bool // Whether any entry was displayed yet
+
+	saveFailed bool // Whether saving checkpoints failed, reported once
+
+	stats *liveStats // Counts of the matched entries, with StatsEvery
+
+	alertEnc *json.Encoder // Writes alerts to AlertLog
+	backlog  bool          // Whether the lines being read predate the watch
 }
 
 // NewWatcher creates a new file watcher
 func NewWatcher(config *Config) *Watcher {
-	return &Watcher{
-		config: config,
-		parser: parser.DetectParser(""), // Will auto-detect
+	if config.Matcher == nil && config.Pattern != "" {
+		config.Matcher = matcher.NewLiteral(config.Pattern)
 	}
-}
 
-// Watch starts watching the file for changes
-func (w *Watcher) Watch(ctx context.Context) error {
-	// Open file
-	file, err := os.Open(w.config.FilePath)
-	if err != nil {
-		return fmt.Errorf("failed to open file: %w", err)
+	w := &Watcher{
+		config:   config,
+		detector: parser.NewDetector(config.ParserOptions),
+		byPath:   make(map[string]*tail),
+		dirs:     make(map[string]bool),
 	}
-	defer file.Close()
-
-	w.file = file
 
-	// Seek to end of file (like tail -f)
-	if !w.config.ShowAll {
-		fileInfo, err := file.Stat()
-		if err != nil {
-			return fmt.Errorf("failed to get file info: %w", err)
-		}
-		w.lastOffset = fileInfo.Size()
-		if _, err := file.Seek(w.lastOffset, 0); err != nil {
-			return fmt.Errorf("failed to seek: %w", err)
+	// Several files are expected when given several paths, a directory or a glob
+	w.multi = len(config.Paths) > 1
+	for _, path := range config.Paths {
+		if info, err := os.Stat(path); isGlob(path) || (err == nil && info.IsDir()) {
+			w.multi = true
 		}
 	}
+	if w.multi {
+		w.reorder.window = config.Reorder
+	}
+	if config.StatsEvery > 0 {
+		w.stats = newLiveStats(time.Now())
+	}
+	if config.AlertLog != nil {
+		w.alertEnc = json.NewEncoder(config.AlertLog)
+	}
+	return w
+}
 
+// Watch starts watching the files for changes
+func (w *Watcher) Watch(ctx context.Context) error {
 	// Create file system watcher
 	fsWatcher, err := fsnotify.NewWatcher()
 	if err != nil {
 		return fmt.Errorf("failed to create watcher: %w", err)
 	}
 	defer fsWatcher.Close()
+	w.fsWatcher = fsWatcher
+	defer w.closeAll()
 
-	// Add file to watcher
-	if err := fsWatcher.Add(w.config.FilePath); err != nil {
-		return fmt.Errorf("failed to watch file: %w", err)
+	// Follow the files present now from their end, unless showing everything
+	if err := w.discover(true); err != nil {
+		return err
+	}
+	if len(w.tails) == 0 && len(w.dirs) == 0 {
+		return fmt.Errorf("no files to watch")
 	}
 
-	fmt.Printf("🔍 Watching %s for changes...\n", w.config.FilePath)
+	if w.multi {
+		fmt.Printf("🔍 Watching %d files for changes...\n", len(w.tails))
+		for _, t := range w.tails {
+			fmt.Printf("   %s %s\n", t.color.Sprint("●"), t.path)
+		}
+		fmt.Println("📂 New files under the watched paths are picked up")
+	} else {
+		fmt.Printf("🔍 Watching %s for changes...\n", w.tails[0].path)
+	}
 	if w.config.Pattern != "" {
 		fmt.Printf("🎯 Filtering for pattern: %s\n", w.config.Pattern)
 	}
 	if w.config.MinLevel != models.UNKNOWN {
 		fmt.Printf("📊 Minimum level: %s\n", w.config.MinLevel)
 	}
+	if !w.config.StartTime.IsZero() || !w.config.EndTime.IsZero() {
+		fmt.Printf("⏰ Time range: %s → %s\n", formatBound(w.config.StartTime), formatBound(w.config.EndTime))
+	}
+	if w.config.Alerts != nil {
+		fmt.Printf("🚨 Evaluating %d alert rules\n", w.config.Alerts.Len())
+	}
+	for _, t := range w.tails {
+		if t.resumed && t.next.Line > 0 {
+			fmt.Printf("⏯️  Resuming %s at line %d\n", t.path, t.next.Line)
+		} else if t.resumed {
+			fmt.Printf("⏯️  Resuming %s at byte %d\n", t.path, t.next.Offset)
+		}
+	}
 	fmt.Println("Press Ctrl+C to stop")
 	fmt.Println(color.New(color.FgCyan).Sprint("───────────────────────────────────────────────"))
 
+	// Show the existing content with --all, or since the checkpoint
+	w.backlog = true
+	for _, t := range w.tails {
+		w.readNewLines(t)
+	}
+	w.backlog = false
+
 	ticker := time.NewTicker(w.config.Interval)
 	defer ticker.Stop()
 
+	// Reordered entries are released a few times per window
+	var release <-chan time.Time
+	if w.reorder.window > 0 {
+		releaseTicker := time.NewTicker(max(w.reorder.window/4, 10*time.Millisecond))
+		defer releaseTicker.Stop()
+		release = releaseTicker.C
+	}
+
+	var statsTick <-chan time.Time
+	if w.stats != nil {
+		statsTicker := time.NewTicker(w.config.StatsEvery)
+		defer statsTicker.Stop()
+		statsTick = statsTicker.C
+	}
+
 	for {
 		select {
 		case <-ctx.Done():
+			for _, t := range w.tails {
+				w.flushPending(t)
+			}
+			for _, entry := range w.reorder.Drain() {
+				w.displayEntry(entry)
+			}
+			w.saveCheckpoints()
+			if w.stats != nil {
+				w.stats.Summary(time.Now())
+			}
 			return nil
 
 		case event := <-fsWatcher.Events:
-			if event.Op&fsnotify.Write == fsnotify.Write {
-				w.readNewLines()
-			}
+			w.handleEvent(event)
 
 		case err := <-fsWatcher.Errors:
 			return fmt.Errorf("watcher error: %w", err)
 
 		case <-ticker.C:
-			// Periodic check (fallback in case events are missed)
-			w.readNewLines()
+			// Periodic check (fallback in case events are missed);
+			// an idle file means any buffered multiline entry is complete
+			w.discover(false)
+			for _, t := range w.tails {
+				w.readNewLines(t)
+				if time.Since(t.lastRead) >= w.config.Interval {
+					w.flushPending(t)
+				}
+			}
+			w.release(time.Now())
+			w.evaluateAlerts()
+			w.saveCheckpoints()
+
+		case <-release:
+			w.release(time.Now())
+
+		case now := <-statsTick:
+			w.stats.Report(now)
 		}
 	}
 }
 
-// readNewLines reads new lines added to the file
-func (w *Watcher) readNewLines() {
-	// Get current file size
-	fileInfo, err := w.file.Stat()
-	if err != nil {
-		return
+// handleEvent reacts to a change in a watched directory. Any event on a
+// followed path is a reason to read: writes append lines, and renames,
+// removals and creations may be a rotation.
+func (w *Watcher) handleEvent(event fsnotify.Event) {
+	path := filepath.Clean(event.Name)
+	if event.Op&fsnotify.Create == fsnotify.Create {
+		w.discover(false)
+	}
+	if t, ok := w.byPath[path]; ok {
+		w.readNewLines(t)
 	}
+}
 
-	currentSize := fileInfo.Size()
+// discover follows files that newly match the watched paths. Files with a
+// checkpoint resume from it. Otherwise the initial files start at their end
+// unless ShowAll is set, and failing to open one is an error; files found
+// later are read from the start, and those that cannot be opened yet are
+// retried on the next call.
+func (w *Watcher) discover(initial bool) error {
+	files, dirs := expandPaths(w.config.Paths)
+	for _, dir := range dirs {
+		w.watchDir(dir)
+	}
 
-	// Check if file was truncated (log rotation)
-	if currentSize < w.lastOffset {
-		w.lastOffset = 0
-		w.file.Seek(0, 0)
+	for _, path := range files {
+		if _, ok := w.byPath[path]; ok {
+			continue
+		}
+		w.drainRenamed(path)
+		t, err := w.follow(path, initial && !w.config.ShowAll)
+		if err != nil {
+			if initial {
+				return fmt.Errorf("%s: %w", path, err)
+			}
+			continue
+		}
+		w.tails = append(w.tails, t)
+		w.byPath[path] = t
+		w.watchDir(filepath.Dir(path))
+		w.nameSources()
+
+		if !initial {
+			fmt.Printf("%s %s\n", color.New(color.FgHiBlack).Sprint("📄 Now watching"), t.color.Sprint(t.path))
+			w.readNewLines(t)
+		}
+	}
+	return nil
+}
+
+// watchDir subscribes to changes in a directory; files are watched through
+// their directory so that new files are seen too
+func (w *Watcher) watchDir(dir string) {
+	if w.dirs[dir] {
+		return
 	}
+	if err := w.fsWatcher.Add(dir); err == nil {
+		w.dirs[dir] = true
+	}
+}
 
-	// Read new content
-	scanner := bufio.NewScanner(w.file)
-	for scanner.Scan() {
-		line := scanner.Text()
-		w.processLine(line)
+// nameSources gives every followed file a short name, unique among them
+func (w *Watcher) nameSources() {
+	paths := make([]string, len(w.tails))
+	for i, t := range w.tails {
+		paths[i] = t.path
+	}
+	names := input.SourceNames(paths)
+	w.width = 0
+	for _, t := range w.tails {
+		t.source = names[t.path]
+		w.width = max(w.width, len(t.source))
 	}
+}
 
-	// Update offset
-	w.lastOffset = currentSize
+// closeAll closes the followed files
+func (w *Watcher) closeAll() {
+	for _, t := range w.tails {
+		t.file.Close()
+	}
 }
 
-// processLine processes a single log line
-func (w *Watcher) processLine(line string) {
-	if line == "" {
+// show displays an entry, through the reorder buffer if enabled
+func (w *Watcher) show(entry *models.LogEntry) {
+	if w.reorder.window <= 0 {
+		w.displayEntry(entry)
 		return
 	}
+	w.reorder.Push(entry, time.Now())
+}
 
-	// Auto-detect parser
-	currentParser := parser.DetectParser(line)
+// release displays the buffered entries whose reorder window has passed
+func (w *Watcher) release(now time.Time) {
+	for {
+		entry, ok := w.reorder.Pop(now)
+		if !ok {
+			return
+		}
+		w.displayEntry(entry)
+	}
+}
 
-	// Parse line
-	entry, err := currentParser.Parse(line, w.config.FilePath)
-	if err != nil {
-		return
+// shouldInclude checks if an entry passes the filters
+func (w *Watcher) shouldInclude(entry *models.LogEntry) bool {
+	if w.config.MinLevel != models.UNKNOWN && !entry.Level.AtLeast(w.config.MinLevel) {
+		return false
 	}
 
-	// Apply filters
-	if w.config.MinLevel != models.UNKNOWN && entry.Level < w.config.MinLevel {
-		return
+	if w.config.Matcher != nil && !matcher.MatchEntry(w.config.Matcher, entry) {
+		return false
 	}
 
-	if w.config.Pattern != "" && !entry.MatchesPattern(w.config.Pattern) {
-		return
+	if !w.config.StartTime.IsZero() && entry.Timestamp.Before(w.config.StartTime) {
+		return false
+	}
+	if !w.config.EndTime.IsZero() && entry.Timestamp.After(w.config.EndTime) {
+		return false
 	}
 
-	// Display the entry with color
-	w.displayEntry(entry)
+	return true
+}
+
+// formatBound formats a time range bound, showing open bounds as "…"
+func formatBound(t time.Time) string {
+	if t.IsZero() {
+		return "…"
+	}
+	return t.Format(time.RFC3339)
 }
 
 // displayEntry displays a log entry with color coding
@@ -181,69 +393,25 @@ func (w *Watcher) displayEntry(entry *models.LogEntry) {
 		levelColor = color.New(color.FgWhite)
 	}
 
-	fmt.Printf("%s %s %s\n",
-		color.New(color.FgHiBlack).Sprintf("[%s]", timestamp),
-		levelColor.Sprintf("%-5s", entry.Level),
-		entry.Message,
-	)
-}
-
-// WatchWithStats watches file and displays periodic statistics
-func (w *Watcher) WatchWithStats(ctx context.Context, statsInterval time.Duration) error {
-	stats := models.NewStatistics()
-
-	// Channel for log entries
-	entryChan := make(chan *models.LogEntry, 100)
-
-	// Start stats printer goroutine
-	go func() {
-		ticker := time.NewTicker(statsInterval)
-		defer ticker.Stop()
-
-		for {
-			select {
-			case <-ctx.Done():
-				return
-			case entry := <-entryChan:
-				stats.AddEntry(entry)
-			case <-ticker.C:
-				w.printStats(stats)
-			}
-		}
-	}()
-
-	// Start watching (simplified version)
-	file, err := os.Open(w.config.FilePath)
-	if err != nil {
-		return err
+	// Separate runs of matches and their context; context is dimmed
+	if entry.GroupStart && w.displayed {
+		fmt.Println(color.New(color.FgHiBlack).Sprint("--"))
 	}
-	defer file.Close()
-
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		select {
-		case <-ctx.Done():
-			return nil
-		default:
-			line := scanner.Text()
-			if entry, err := parser.DetectParser(line).Parse(line, w.config.FilePath); err == nil {
-				entryChan <- entry
-				w.displayEntry(entry)
-			}
-		}
+	w.displayed = true
+	message := entry.Message
+	if entry.Context {
+		message = color.New(color.FgHiBlack).Sprint(message)
 	}
 
-	return scanner.Err()
-}
+	// Prefix the source when following several files
+	prefix := color.New(color.FgHiBlack).Sprintf("[%s]", timestamp)
+	if t, ok := w.byPath[entry.Path]; ok && w.multi {
+		prefix += " " + t.color.Sprintf("%-*s", w.width, entry.Source)
+	}
 
-// printStats prints current statistics
-func (w *Watcher) printStats(stats *models.Statistics) {
-	fmt.Println(color.New(color.FgCyan).Sprint("\n───────────────────────────────────────────────"))
-	fmt.Printf("📊 Stats (last %d entries)\n", stats.TotalEntries)
-	fmt.Printf("   ERROR: %d | WARN: %d | INFO: %d\n",
-		stats.GetLevelCount(models.ERROR),
-		stats.GetLevelCount(models.WARN),
-		stats.GetLevelCount(models.INFO),
+	fmt.Printf("%s %s %s\n",
+		prefix,
+		levelColor.Sprintf("%-5s", entry.Level),
+		message,
 	)
-	fmt.Println(color.New(color.FgCyan).Sprint("───────────────────────────────────────────────\n"))
 }